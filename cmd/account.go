@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+
+	"nacos-cli/pkg/printer"
+
+	"github.com/spf13/cobra"
+)
+
+var accountCmd = &cobra.Command{
+	Use:   "account",
+	Short: "服务端用户管理",
+	Long: `管理Nacos服务端上的用户账号，需要管理员权限。
+本地登录凭据请使用 user 命令管理。密码不会通过命令行参数传入，
+请在提示时输入，或使用 --password-stdin 从标准输入读取。`,
+}
+
+var listAccountCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出用户",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		pageNo, _ := cmd.Flags().GetInt("page")
+		pageSize, _ := cmd.Flags().GetInt("size")
+		search, _ := cmd.Flags().GetString("search")
+
+		page, err := client.ListUsers(pageNo, pageSize, search)
+		if err != nil {
+			return err
+		}
+
+		table := printer.NewTable("用户名")
+		for _, user := range page.PageItems {
+			table.AddRow(user.Username)
+		}
+		return printOutput(&printer.Output{
+			Object: page.PageItems,
+			Table:  table,
+			Empty:  "没有找到用户",
+			Footer: fmt.Sprintf("共 %d 个用户，第 %d/%d 页", page.TotalCount, page.PageNumber, page.PagesAvailable),
+		})
+	},
+}
+
+var createAccountCmd = &cobra.Command{
+	Use:   "create [username]",
+	Short: "创建用户",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		password, err := readPassword(cmd, fmt.Sprintf("请输入用户 %s 的密码: ", args[0]), true)
+		if err != nil {
+			return err
+		}
+
+		client := createClient()
+		_, err = ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		if err := client.CreateUser(args[0], password); err != nil {
+			return err
+		}
+
+		fmt.Printf("用户 %s 创建成功\n", args[0])
+		return nil
+	},
+}
+
+var deleteAccountCmd = &cobra.Command{
+	Use:   "delete [username]",
+	Short: "删除用户",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		if !confirmAction(cmd, fmt.Sprintf("确定要删除用户 %s 吗？此操作不可恢复！(y/N): ", args[0])) {
+			fmt.Println("操作已取消")
+			return nil
+		}
+
+		if err := client.DeleteUser(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("用户 %s 删除成功\n", args[0])
+		return nil
+	},
+}
+
+var passwdAccountCmd = &cobra.Command{
+	Use:   "passwd [username]",
+	Short: "修改用户密码",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		password, err := readPassword(cmd, fmt.Sprintf("请输入用户 %s 的新密码: ", args[0]), true)
+		if err != nil {
+			return err
+		}
+
+		client := createClient()
+		_, err = ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		if err := client.UpdateUserPassword(args[0], password); err != nil {
+			return err
+		}
+
+		fmt.Printf("用户 %s 的密码已修改\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(accountCmd)
+
+	accountCmd.AddCommand(listAccountCmd)
+	accountCmd.AddCommand(createAccountCmd)
+	accountCmd.AddCommand(deleteAccountCmd)
+	accountCmd.AddCommand(passwdAccountCmd)
+
+	listAccountCmd.Flags().Int("page", 1, "页码")
+	listAccountCmd.Flags().Int("size", 20, "每页大小")
+	listAccountCmd.Flags().String("search", "", "按用户名模糊搜索")
+
+	createAccountCmd.Flags().Bool("password-stdin", false, "从标准输入读取密码")
+	passwdAccountCmd.Flags().Bool("password-stdin", false, "从标准输入读取密码")
+
+	deleteAccountCmd.Flags().BoolP("yes", "y", false, "跳过确认")
+}
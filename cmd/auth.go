@@ -0,0 +1,388 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"nacos-cli/pkg/nacos"
+	"nacos-cli/pkg/printer"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "鉴权管理",
+	Long:  `以声明式文件管理Nacos的用户、角色绑定和权限`,
+}
+
+var planAuthCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "比较RBAC声明与服务端状态",
+	Long:  `读取 -f 指定的RBAC声明文件，列出使服务端收敛到声明状态所需的变更，不做任何修改`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, _, _, actions, err := planRBAC(cmd)
+		if err != nil {
+			return err
+		}
+
+		table := printer.NewTable("类型", "用户", "角色", "资源", "操作")
+		names := make([]string, 0, len(actions))
+		for _, action := range actions {
+			table.AddRow(action.Type, action.Username, action.Role, action.Resource, action.Action)
+			names = append(names, action.String())
+		}
+		if actions == nil {
+			actions = []nacos.RBACAction{}
+		}
+
+		return printOutput(&printer.Output{
+			Object: actions,
+			Table:  table,
+			Names:  names,
+			Text: func(w io.Writer, wide bool) error {
+				if len(actions) == 0 {
+					_, err := fmt.Fprintln(w, "服务端已与声明一致，无需变更")
+					return err
+				}
+				for _, name := range names {
+					fmt.Fprintln(w, name)
+				}
+				_, err := fmt.Fprintf(w, "\n共 %d 项变更，运行 'nacos-cli auth apply' 应用到 %s\n", len(actions), client.ServerURL)
+				return err
+			},
+		})
+	},
+}
+
+var applyAuthCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "将RBAC声明应用到服务端",
+	Long: `读取 -f 指定的RBAC声明文件，创建用户、绑定角色、授予权限，使服务端收敛到声明状态。
+指定 --prune 时同时收回声明之外的权限、解除绑定并删除用户；
+不会删除当前登录用户，也不会解除当前用户的管理员角色。
+解除 ROLE_ADMIN 绑定或删除持有 ROLE_ADMIN 的用户需要同时指定 --allow-admin-removal。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, spec, state, actions, err := planRBAC(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(actions) == 0 {
+			fmt.Println("服务端已与声明一致，无需变更")
+			return nil
+		}
+
+		// 拒绝移除当前用户自身的访问权限，避免把自己锁在外面
+		for _, action := range actions {
+			if action.Type == nacos.ActionUnbindRole && action.Role == nacos.AdminRole && action.Username == client.Username {
+				return fmt.Errorf("拒绝解除当前用户 %s 的 %s 角色，请在声明文件中保留该绑定", client.Username, nacos.AdminRole)
+			}
+			if action.Type == nacos.ActionDeleteUser && action.Username == client.Username {
+				return fmt.Errorf("拒绝删除当前用户 %s，请在声明文件中保留该用户", client.Username)
+			}
+		}
+		// 使用 AK/SK 等鉴权方式时不知道当前用户，因此任何管理员都不允许默认移除，避免删掉内置的 nacos 账号
+		if allow, _ := cmd.Flags().GetBool("allow-admin-removal"); !allow {
+			if removals := nacos.AdminRemovals(state, actions); len(removals) > 0 {
+				var lines []string
+				for _, action := range removals {
+					lines = append(lines, "  "+action.String())
+				}
+				return fmt.Errorf("拒绝移除 %s 角色的持有者，请在声明文件中保留，或使用 --allow-admin-removal 确认:\n%s",
+					nacos.AdminRole, strings.Join(lines, "\n"))
+			}
+		}
+
+		for _, action := range actions {
+			fmt.Println(action)
+		}
+		if !confirmAction(cmd, fmt.Sprintf("确定要将以上 %d 项变更应用到 %s 吗？(y/N): ", len(actions), client.ServerURL)) {
+			fmt.Println("操作已取消")
+			return nil
+		}
+
+		passwordEnv := make(map[string]string)
+		for _, u := range spec.Users {
+			passwordEnv[u.Username] = u.PasswordEnv
+		}
+
+		for _, action := range actions {
+			if err := applyRBACAction(cmd, client, action, passwordEnv[action.Username]); err != nil {
+				return fmt.Errorf("%s: %w", action, err)
+			}
+			fmt.Printf("完成: %s\n", action)
+		}
+
+		fmt.Printf("已应用 %d 项变更\n", len(actions))
+		return nil
+	},
+}
+
+var whoamiAuthCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "显示当前用户的身份",
+	Long:  `显示当前凭据对应的用户名、是否为全局管理员、绑定的角色和令牌过期时间`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		loginResp, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		identity := struct {
+			Server      string     `json:"server"`
+			AuthMethod  string     `json:"authMethod"`
+			Username    string     `json:"username,omitempty"`
+			GlobalAdmin bool       `json:"globalAdmin"`
+			Roles       []string   `json:"roles,omitempty"`
+			TokenExpiry *time.Time `json:"tokenExpiry,omitempty"`
+		}{
+			Server:     client.ServerURL,
+			AuthMethod: viper.GetString("auth.method"),
+			Username:   client.Username,
+		}
+		if identity.AuthMethod == "" {
+			identity.AuthMethod = authPassword
+		}
+
+		var rolesErr error
+		if usesToken(client) {
+			identity.GlobalAdmin = loginResp.GlobalAdmin
+			if client.TokenExpiry > 0 {
+				expiry := time.Unix(client.TokenExpiry, 0)
+				identity.TokenExpiry = &expiry
+			}
+			if client.Username != "" {
+				identity.Roles, rolesErr = client.UserRoles(client.Username)
+			}
+		}
+
+		return printOutput(&printer.Output{
+			Object: identity,
+			Names:  []string{identity.Username},
+			Text: func(w io.Writer, wide bool) error {
+				fields := []printer.Field{{Name: "服务器", Value: identity.Server}}
+				if !usesToken(client) {
+					fields = append(fields, printer.Field{Name: "鉴权方式", Value: identity.AuthMethod + "，服务端不返回用户身份"})
+					return printer.PrintFields(w, fields)
+				}
+
+				username := identity.Username
+				if username == "" {
+					username = "(未知)"
+				}
+				roles := strings.Join(identity.Roles, ", ")
+				if rolesErr != nil {
+					roles = fmt.Sprintf("无法获取 (%v)", rolesErr)
+				} else if roles == "" {
+					roles = "(无)"
+				}
+				expiry := "未知"
+				if identity.TokenExpiry != nil {
+					expiry = fmt.Sprintf("%s (剩余 %s)", identity.TokenExpiry.Format("2006-01-02 15:04:05"),
+						time.Until(*identity.TokenExpiry).Round(time.Second))
+				}
+				fields = append(fields,
+					printer.Field{Name: "用户名", Value: username},
+					printer.Field{Name: "全局管理员", Value: identity.GlobalAdmin},
+					printer.Field{Name: "角色", Value: roles},
+					printer.Field{Name: "令牌过期时间", Value: expiry},
+				)
+				return printer.PrintFields(w, fields)
+			},
+		})
+	},
+}
+
+var canIAuthCmd = &cobra.Command{
+	Use:   "can-i <r|w> <namespace>[:group[:dataId]]",
+	Short: "检查当前用户是否有权限",
+	Long: `在客户端按当前用户的角色权限判断能否读(r)或写(w)指定资源，有权限时输出 yes 并返回0，否则输出 no 并返回1，出错时返回2。
+省略分组或配置ID表示需要整个命名空间（或分组）的权限，默认命名空间可写作 public。
+查询角色和权限需要管理员权限，普通用户无法查询时返回错误。`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: map[string]string{annotationResultExitCode: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		action, resource := args[0], args[1]
+		if action != "r" && action != "w" {
+			return fmt.Errorf("权限动作必须是 r 或 w")
+		}
+
+		client := createClient()
+		loginResp, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		review, err := reviewAccess(client, loginResp)
+		if err != nil {
+			return err
+		}
+
+		if review.Can(action, resource) {
+			fmt.Println("yes")
+			return nil
+		}
+		fmt.Println("no")
+		return negativeResult(cmd)
+	},
+}
+
+// 获取当前用户的身份、角色和权限
+func reviewAccess(client *nacos.Client, loginResp *nacos.LoginResponse) (*nacos.AccessReview, error) {
+	if !usesToken(client) {
+		return nil, fmt.Errorf("当前鉴权方式无法获取用户身份，不能检查权限")
+	}
+
+	review := &nacos.AccessReview{Username: client.Username, GlobalAdmin: loginResp.GlobalAdmin}
+	if review.GlobalAdmin {
+		return review, nil
+	}
+	if client.Username == "" {
+		return nil, fmt.Errorf("未知的用户名，不能检查权限")
+	}
+
+	roles, err := client.UserRoles(client.Username)
+	if err != nil {
+		return nil, fmt.Errorf("获取用户角色失败: %w", err)
+	}
+	review.Roles = roles
+
+	permissions, err := client.RolePermissions(roles)
+	if err != nil {
+		return nil, fmt.Errorf("获取角色权限失败: %w", err)
+	}
+	review.Permissions = permissions
+	return review, nil
+}
+
+// preflightAccess 在批量操作前检查当前用户对资源的权限。
+// 无法确认权限时（例如鉴权方式不返回身份、普通用户无权查询角色）只输出警告，不阻止操作
+func preflightAccess(cmd *cobra.Command, client *nacos.Client, loginResp *nacos.LoginResponse, action string, resources []string) error {
+	if skip, _ := cmd.Flags().GetBool("skip-preflight"); skip || len(resources) == 0 {
+		return nil
+	}
+
+	review, err := reviewAccess(client, loginResp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 无法预检权限: %v\n", err)
+		return nil
+	}
+
+	var denied []string
+	for _, resource := range resources {
+		if !review.Can(action, resource) {
+			denied = append(denied, resource)
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("权限预检失败，用户 %s 没有以下资源的 %s 权限: %s（可使用 --skip-preflight 跳过检查）",
+			client.Username, action, strings.Join(denied, ", "))
+	}
+	return nil
+}
+
+// 读取声明文件，登录后计算变更
+func planRBAC(cmd *cobra.Command) (*nacos.Client, *nacos.RBACSpec, *nacos.RBACState, []nacos.RBACAction, error) {
+	spec, err := loadRBACSpec(cmd)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	client := createClient()
+	loginResp, err := ensureLogin(client)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("登录失败: %w", err)
+	}
+
+	// 管理用户、角色和权限需要全局管理员
+	skip, _ := cmd.Flags().GetBool("skip-preflight")
+	if !skip && usesToken(client) && !loginResp.GlobalAdmin {
+		return nil, nil, nil, nil, fmt.Errorf("权限预检失败，用户 %s 不是全局管理员，无法管理用户、角色和权限（可使用 --skip-preflight 跳过检查）", client.Username)
+	}
+
+	state, err := client.FetchRBACState()
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	prune, _ := cmd.Flags().GetBool("prune")
+	return client, spec, state, nacos.PlanRBAC(spec, state, prune), nil
+}
+
+func loadRBACSpec(cmd *cobra.Command) (*nacos.RBACSpec, error) {
+	file, _ := cmd.Flags().GetString("file")
+	if file == "" {
+		return nil, fmt.Errorf("必须使用 -f 指定RBAC声明文件")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	var spec nacos.RBACSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("解析RBAC声明失败: %w", err)
+	}
+	if err := spec.Normalize(); err != nil {
+		return nil, fmt.Errorf("RBAC声明不正确: %w", err)
+	}
+
+	return &spec, nil
+}
+
+func applyRBACAction(cmd *cobra.Command, client *nacos.Client, action nacos.RBACAction, passwordEnv string) error {
+	switch action.Type {
+	case nacos.ActionCreateUser:
+		password := ""
+		if passwordEnv != "" {
+			password = os.Getenv(passwordEnv)
+			if password == "" {
+				return fmt.Errorf("环境变量 %s 未设置", passwordEnv)
+			}
+		} else {
+			var err error
+			password, err = readPassword(cmd, fmt.Sprintf("请输入新用户 %s 的密码: ", action.Username), true)
+			if err != nil {
+				return err
+			}
+		}
+		return client.CreateUser(action.Username, password)
+	case nacos.ActionDeleteUser:
+		return client.DeleteUser(action.Username)
+	case nacos.ActionBindRole:
+		return client.BindRole(action.Role, action.Username)
+	case nacos.ActionUnbindRole:
+		return client.UnbindRole(action.Role, action.Username)
+	case nacos.ActionGrant:
+		return client.GrantPermission(action.Role, action.Resource, action.Action)
+	case nacos.ActionRevoke:
+		return client.RevokePermission(action.Role, action.Resource, action.Action)
+	default:
+		return fmt.Errorf("未知的变更类型: %s", action.Type)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+
+	authCmd.AddCommand(planAuthCmd)
+	authCmd.AddCommand(applyAuthCmd)
+	authCmd.AddCommand(whoamiAuthCmd)
+	authCmd.AddCommand(canIAuthCmd)
+
+	for _, c := range []*cobra.Command{planAuthCmd, applyAuthCmd} {
+		c.Flags().StringP("file", "f", "", "RBAC声明文件")
+		c.Flags().Bool("prune", false, "删除声明之外的用户、角色绑定和权限")
+		c.Flags().Bool("skip-preflight", false, "跳过权限预检")
+	}
+	applyAuthCmd.Flags().BoolP("yes", "y", false, "跳过确认")
+	applyAuthCmd.Flags().Bool("allow-admin-removal", false, "允许解除 ROLE_ADMIN 绑定和删除管理员用户")
+}
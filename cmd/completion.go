@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nacos-cli/pkg/completioncache"
+	"nacos-cli/pkg/nacos"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// 补全列表的有效期，过期后重新请求服务端
+	completionTTL = 30 * time.Second
+	// 补全时请求服务端的超时时间，超时后使用过期的缓存
+	completionTimeout = 2 * time.Second
+	// 补全配置ID时最多读取的配置数量
+	completionMaxConfigs = 2000
+)
+
+// 补全配置ID和分组：第一个参数补全配置ID，第二个参数补全该配置所在的分组
+func completeConfigArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	configs := cachedCompletions("configs", listConfigKeys)
+
+	seen := make(map[string]bool)
+	var values []string
+	for _, key := range configs {
+		group, dataID, ok := strings.Cut(key, "@")
+		if !ok {
+			continue
+		}
+		value := dataID
+		if len(args) == 1 {
+			if dataID != resolveDataID(args[0]) {
+				continue
+			}
+			value = group
+		}
+		if !seen[value] && strings.HasPrefix(value, toComplete) {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+	return values, cobra.ShellCompDirectiveNoFileComp
+}
+
+// 补全命名空间ID，名称作为说明显示
+func completeNamespaces(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var values []string
+	for _, value := range cachedCompletions("namespaces", listNamespaceIDs) {
+		if strings.HasPrefix(value, toComplete) {
+			values = append(values, value)
+		}
+	}
+	return values, cobra.ShellCompDirectiveNoFileComp
+}
+
+// 读取补全列表，缓存未过期时直接使用；请求服务端失败时使用过期的缓存，都没有时返回空，
+// 补全不应因为网络或登录问题报错。失败后在缓存有效期内不再请求服务端
+func cachedCompletions(kind string, fetch func(client *nacos.Client) ([]string, error)) []string {
+	// 补全时命令行参数在初始化之后才解析，重新读取配置以使 --context、--namespace 等参数生效
+	if err := reloadSettings(); err != nil {
+		return nil
+	}
+	key := strings.Join([]string{activeContext(), viper.GetString("server"), viper.GetString("namespace"), kind}, "|")
+	cache := openCompletionCache()
+	values, fresh, ok := cache.Get(key)
+	if ok && fresh {
+		return values
+	}
+
+	// 只使用缓存的令牌或不需要凭据存储的密码，令牌过期时使用过期的补全缓存
+	skipCredentialStore = true
+	client := createClient()
+	httpClient := *client.HTTPClient
+	httpClient.Timeout = completionTimeout
+	client.HTTPClient = &httpClient
+	latest, err := func() ([]string, error) {
+		if _, err := ensureLogin(client); err != nil {
+			return nil, err
+		}
+		return fetch(client)
+	}()
+	if err != nil {
+		// 记录失败，有效期内的补全直接使用过期的缓存，不再等待超时
+		cache.Fail(key)
+		return values
+	}
+	cache.Put(key, latest)
+	return latest
+}
+
+// 打开补全缓存，位于令牌缓存所在的目录
+func openCompletionCache() *completioncache.Cache {
+	dir := filepath.Join(os.TempDir(), "nacos-cli")
+	if home, err := os.UserHomeDir(); err == nil {
+		dir = filepath.Join(home, ".nacos-cli")
+	}
+	if tokenCacheFile := viper.GetString("tokenCacheFile"); tokenCacheFile != "" {
+		dir = filepath.Dir(tokenCacheFile)
+	}
+	return completioncache.New(filepath.Join(dir, "completion.json"), completionTTL)
+}
+
+// 当前命名空间中所有配置的 group@dataId
+func listConfigKeys(client *nacos.Client) ([]string, error) {
+	const pageSize = 500
+	var keys []string
+	for pageNo := 1; len(keys) < completionMaxConfigs; pageNo++ {
+		configs, err := client.ListConfigs(pageNo, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, config := range configs {
+			keys = append(keys, config.Group+"@"+config.DataID)
+		}
+		if len(configs) < pageSize {
+			break
+		}
+	}
+	return keys, nil
+}
+
+// 所有命名空间的ID，以制表符分隔名称作为补全的说明。public 命名空间的ID为空，不参与补全
+func listNamespaceIDs(client *nacos.Client) ([]string, error) {
+	namespaces, err := client.ListNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, ns := range namespaces {
+		if ns.Namespace == "" {
+			continue
+		}
+		value := ns.Namespace
+		if ns.NamespaceShowName != "" && ns.NamespaceShowName != ns.Namespace {
+			value += "\t" + ns.NamespaceShowName
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -2,13 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"nacos-cli/pkg/nacos"
+	"nacos-cli/pkg/printer"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,25 +22,44 @@ var configCmd = &cobra.Command{
 }
 
 var getConfigCmd = &cobra.Command{
-	Use:   "get [dataId] [group]",
-	Short: "获取配置",
-	Args:  cobra.ExactArgs(2),
+	Use:               "get [dataId] [group]",
+	Short:             "获取配置",
+	Long:              `获取配置。省略分组时使用配置项 group（可在项目配置文件中设置），默认为 DEFAULT_GROUP`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeConfigArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		_, err := client.Login()
+		_, err := ensureLogin(client)
 		if err != nil {
 			return err
 		}
 
-		config, err := client.GetConfig(args[0], args[1])
+		config, err := client.GetConfigDetail(resolveDataID(args[0]), groupArg(args, 1))
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("DataID: %s\n", config.DataID)
-		fmt.Printf("Group: %s\n", config.Group)
-		fmt.Printf("Content:\n%s\n", config.Content)
-		return nil
+		table := printer.NewTable("DataID", "Group", "Type", "MD5*", "修改时间*")
+		table.AddRow(config.DataID, config.Group, config.Type, config.MD5, formatMillis(config.ModifyTime))
+		return printOutput(&printer.Output{
+			Object: config,
+			Table:  table,
+			Text: func(w io.Writer, wide bool) error {
+				fmt.Fprintf(w, "DataID: %s\n", config.DataID)
+				fmt.Fprintf(w, "Group: %s\n", config.Group)
+				if wide {
+					fmt.Fprintf(w, "Type: %s\n", config.Type)
+					fmt.Fprintf(w, "MD5: %s\n", config.MD5)
+					fmt.Fprintf(w, "Desc: %s\n", config.Desc)
+					fmt.Fprintf(w, "Tags: %s\n", config.ConfigTags)
+					fmt.Fprintf(w, "CreateUser: %s\n", config.CreateUser)
+					fmt.Fprintf(w, "CreateTime: %s\n", formatMillis(config.CreateTime))
+					fmt.Fprintf(w, "ModifyTime: %s\n", formatMillis(config.ModifyTime))
+				}
+				fmt.Fprintf(w, "Content:\n%s\n", config.Content)
+				return nil
+			},
+		})
 	},
 }
 
@@ -48,11 +68,12 @@ var setConfigCmd = &cobra.Command{
 	Short: "创建或更新配置",
 	Long: `创建或更新Nacos配置。可以直接提供内容或从文件读取内容。
 对于简单的配置，可以直接使用命令行参数；
-对于复杂的配置，建议使用 --file 参数从文件读取，或使用 import 命令导入已导出的配置文件。`,
-	Args: cobra.MinimumNArgs(2),
+对于复杂的配置，建议使用 --file 参数从文件读取，或使用 import 命令导入已导出的配置文件。
+使用 --file 时可以省略分组，此时使用配置项 group，默认为 DEFAULT_GROUP。`,
+	Args: cobra.RangeArgs(1, 3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		_, err := client.Login()
+		_, err := ensureLogin(client)
 		if err != nil {
 			return err
 		}
@@ -75,9 +96,11 @@ var setConfigCmd = &cobra.Command{
 			return fmt.Errorf("必须提供内容参数或使用--file指定文件")
 		}
 
+		dataID := resolveDataID(args[0])
+		group := groupArg(args, 1)
 		config := &nacos.Config{
-			DataID:  args[0],
-			Group:   args[1],
+			DataID:  dataID,
+			Group:   group,
 			Content: content,
 		}
 
@@ -85,43 +108,41 @@ var setConfigCmd = &cobra.Command{
 		if configType != "" {
 			config.Type = configType
 		} else {
-			// 尝试从文件扩展名推断类型
-			if strings.HasSuffix(args[0], ".yml") || strings.HasSuffix(args[0], ".yaml") {
-				config.Type = "yaml"
-			} else if strings.HasSuffix(args[0], ".properties") {
-				config.Type = "properties"
-			} else if strings.HasSuffix(args[0], ".json") {
-				config.Type = "json"
-			} else if strings.HasSuffix(args[0], ".xml") {
-				config.Type = "xml"
-			}
+			config.Type = nacos.InferConfigType(dataID)
 		}
 
+		if err := validateBeforePublish(cmd, config); err != nil {
+			return err
+		}
 		if err := client.PublishConfig(config); err != nil {
 			return err
 		}
 
-		fmt.Printf("配置 %s@%s 设置成功\n", args[0], args[1])
+		fmt.Printf("配置 %s@%s 设置成功\n", dataID, group)
 		return nil
 	},
 }
 
 var deleteConfigCmd = &cobra.Command{
-	Use:   "delete [dataId] [group]",
-	Short: "删除配置",
-	Args:  cobra.ExactArgs(2),
+	Use:               "delete [dataId] [group]",
+	Short:             "删除配置",
+	Long:              `删除配置。省略分组时使用配置项 group（可在项目配置文件中设置），默认为 DEFAULT_GROUP`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: completeConfigArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		_, err := client.Login()
+		_, err := ensureLogin(client)
 		if err != nil {
 			return err
 		}
 
-		if err := client.DeleteConfig(args[0], args[1]); err != nil {
+		dataID := resolveDataID(args[0])
+		group := groupArg(args, 1)
+		if err := client.DeleteConfig(dataID, group); err != nil {
 			return err
 		}
 
-		fmt.Printf("配置 %s@%s 删除成功\n", args[0], args[1])
+		fmt.Printf("配置 %s@%s 删除成功\n", dataID, group)
 		return nil
 	},
 }
@@ -131,7 +152,7 @@ var listConfigCmd = &cobra.Command{
 	Short: "列出配置",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		_, err := client.Login()
+		_, err := ensureLogin(client)
 		if err != nil {
 			return fmt.Errorf("登录失败: %w", err)
 		}
@@ -144,32 +165,15 @@ var listConfigCmd = &cobra.Command{
 			return fmt.Errorf("获取配置列表失败: %w", err)
 		}
 
-		if len(configs) == 0 {
-			fmt.Println("没有找到配置")
-			return nil
-		}
-
-		fmt.Printf("%-40s %-20s %-10s\n", "DataID", "Group", "Type")
-		fmt.Println(strings.Repeat("-", 70))
-		for _, config := range configs {
-			configType := config.Type
-			if configType == "" {
-				// 尝试从文件扩展名推断类型
-				if strings.HasSuffix(config.DataID, ".yml") || strings.HasSuffix(config.DataID, ".yaml") {
-					configType = "yaml"
-				} else if strings.HasSuffix(config.DataID, ".properties") {
-					configType = "properties"
-				} else if strings.HasSuffix(config.DataID, ".json") {
-					configType = "json"
-				} else if strings.HasSuffix(config.DataID, ".xml") {
-					configType = "xml"
-				} else {
-					configType = "text"
-				}
+		table := printer.NewTable("DataID", "Group", "Type", "AppName*", "MD5*")
+		for i := range configs {
+			if configs[i].Type == "" {
+				configs[i].Type = nacos.InferConfigType(configs[i].DataID)
 			}
-			fmt.Printf("%-40s %-20s %-10s\n", config.DataID, config.Group, configType)
+			c := configs[i]
+			table.AddRow(c.DataID, c.Group, c.Type, c.AppName, c.MD5)
 		}
-		return nil
+		return printOutput(&printer.Output{Object: configs, Table: table, Empty: "没有找到配置"})
 	},
 }
 
@@ -180,7 +184,7 @@ var exportConfigCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		_, err := client.Login()
+		_, err := ensureLogin(client)
 		if err != nil {
 			return err
 		}
@@ -253,21 +257,7 @@ var exportConfigCmd = &cobra.Command{
 func exportSingleConfig(outputDir string, config *nacos.Config) error {
 
 	// 确定配置类型
-	configType := config.Type
-	if configType == "" {
-		// 尝试从文件扩展名推断类型
-		if strings.HasSuffix(config.DataID, ".yml") || strings.HasSuffix(config.DataID, ".yaml") {
-			configType = "yaml"
-		} else if strings.HasSuffix(config.DataID, ".properties") {
-			configType = "properties"
-		} else if strings.HasSuffix(config.DataID, ".json") {
-			configType = "json"
-		} else if strings.HasSuffix(config.DataID, ".xml") {
-			configType = "xml"
-		} else {
-			configType = "text"
-		}
-	}
+	configType := remoteConfigType(config, config.DataID)
 
 	// 创建内容文件（直接保存配置内容）
 	// 使用 group@dataId 格式命名文件，保留原始扩展名
@@ -311,7 +301,7 @@ var importConfigCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		_, err := client.Login()
+		loginResp, err := ensureLogin(client)
 		if err != nil {
 			return err
 		}
@@ -320,10 +310,13 @@ var importConfigCmd = &cobra.Command{
 
 		// 检查是否指定了文件
 		file, _ := cmd.Flags().GetString("file")
+		if err := preflightImport(cmd, client, loginResp, inputDir, file); err != nil {
+			return err
+		}
 		if file != "" {
 			// 导入单个文件
 			filePath := filepath.Join(inputDir, file)
-			if err := importSingleFile(client, filePath); err != nil {
+			if err := importSingleFile(cmd, client, filePath); err != nil {
 				return fmt.Errorf("导入文件 %s 失败: %w", file, err)
 			}
 			fmt.Printf("成功导入配置: %s\n", file)
@@ -343,7 +336,7 @@ var importConfigCmd = &cobra.Command{
 			}
 
 			filePath := filepath.Join(inputDir, fileInfo.Name())
-			if err := importSingleFile(client, filePath); err != nil {
+			if err := importSingleFile(cmd, client, filePath); err != nil {
 				fmt.Printf("导入文件 %s 失败: %v\n", fileInfo.Name(), err)
 				continue
 			}
@@ -356,8 +349,36 @@ var importConfigCmd = &cobra.Command{
 	},
 }
 
+// 导入前检查当前用户对将要写入的每个配置是否有写权限
+func preflightImport(cmd *cobra.Command, client *nacos.Client, loginResp *nacos.LoginResponse, inputDir, file string) error {
+	var names []string
+	if file != "" {
+		names = append(names, filepath.Base(file))
+	} else {
+		files, err := ioutil.ReadDir(inputDir)
+		if err != nil {
+			return fmt.Errorf("读取目录失败: %w", err)
+		}
+		for _, fileInfo := range files {
+			if !fileInfo.IsDir() {
+				names = append(names, fileInfo.Name())
+			}
+		}
+	}
+
+	var resources []string
+	for _, name := range names {
+		parts := strings.SplitN(name, "@", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		resources = append(resources, client.Namespace+":"+parts[0]+":"+parts[1])
+	}
+	return preflightAccess(cmd, client, loginResp, "w", resources)
+}
+
 // 导入单个文件的辅助函数
-func importSingleFile(client *nacos.Client, filePath string) error {
+func importSingleFile(cmd *cobra.Command, client *nacos.Client, filePath string) error {
 	// 读取文件内容
 	content, err := ioutil.ReadFile(filePath)
 	if err != nil {
@@ -375,27 +396,16 @@ func importSingleFile(client *nacos.Client, filePath string) error {
 	group := parts[0]
 	dataId := parts[1]
 
-	// 确定配置类型
-	configType := ""
 	// 从dataId的扩展名推断类型
-	if strings.Contains(dataId, ".") {
-		ext := filepath.Ext(dataId)
-		switch strings.ToLower(ext) {
-		case ".yaml", ".yml":
-			configType = "yaml"
-		case ".properties":
-			configType = "properties"
-		case ".json":
-			configType = "json"
-		case ".xml":
-			configType = "xml"
-		default:
-			configType = "text"
-		}
-	}
+	configType := nacos.InferConfigType(dataId)
 
-	// 从文件内容中移除注释头部
+	// 从文件内容中移除注释头部，xml 的头部是一个多行注释
 	fileContent := string(content)
+	if strings.HasPrefix(fileContent, "<!-- Nacos配置\n") {
+		if end := strings.Index(fileContent, "-->"); end >= 0 {
+			fileContent = strings.TrimLeft(fileContent[end+len("-->"):], "\n")
+		}
+	}
 	lines := strings.Split(fileContent, "\n")
 
 	// 跳过注释行
@@ -419,6 +429,10 @@ func importSingleFile(client *nacos.Client, filePath string) error {
 		Type:    configType,
 	}
 
+	if err := validateBeforePublish(cmd, config); err != nil {
+		return err
+	}
+
 	// 发布配置
 	if err := client.PublishConfig(config); err != nil {
 		return fmt.Errorf("发布配置失败: %w", err)
@@ -430,17 +444,39 @@ func importSingleFile(client *nacos.Client, filePath string) error {
 func createClient() *nacos.Client {
 	server := viper.GetString("server")
 	username := viper.GetString("username")
-	password := viper.GetString("password")
 	namespace := viper.GetString("namespace")
-	token := viper.GetString("token")
-	tokenExpiry := viper.GetInt64("tokenExpiry")
 
-	client := nacos.NewClient(server, username, password, namespace)
-
-	// 如果有保存的token且未过期，则使用它
-	if token != "" && tokenExpiry > time.Now().Unix() {
-		client.Token = token
-		client.TokenExpiry = tokenExpiry
+	client := nacos.NewClient(server, username, "", namespace)
+	client.Debug = debug
+	client.Headers = requestHeaders()
+
+	httpClient, err := nacos.NewHTTPClient(tlsConfig())
+	cobra.CheckErr(err)
+	client.HTTPClient = httpClient
+
+	// 按配置项 auth.method 选择鉴权方式
+	switch method := viper.GetString("auth.method"); method {
+	case "", authPassword:
+		// 密码只在需要登录时读取，使用缓存的令牌时不打开凭据存储
+		client.PasswordFunc = func() string { return resolvePassword(server, username) }
+	case authAccessKey:
+		accessKey := viper.GetString("auth.accessKey")
+		client.Auth = &nacos.AccessKeyAuth{
+			AccessKey: accessKey,
+			SecretKey: resolveSecretKey(server, accessKey),
+		}
+	case authExec:
+		client.Auth = &nacos.ExecAuth{
+			Command: viper.GetString("auth.exec.command"),
+			Args:    viper.GetStringSlice("auth.exec.args"),
+			Env:     viper.GetStringSlice("auth.exec.env"),
+			Timeout: viper.GetDuration("auth.exec.timeout"),
+		}
+	case authNone:
+		client.Auth = &nacos.NoAuth{}
+	default:
+		fmt.Fprintf(os.Stderr, "警告: 不支持的鉴权方式 %s，使用用户名密码登录\n", method)
+		client.PasswordFunc = func() string { return resolvePassword(server, username) }
 	}
 
 	return client
@@ -458,11 +494,14 @@ func init() {
 
 	setConfigCmd.Flags().String("type", "", "配置类型 (yaml, properties, json等)")
 	setConfigCmd.Flags().StringP("file", "f", "", "从文件读取配置内容")
+	setConfigCmd.Flags().Bool("no-validate", false, "不检查配置的语法和 schema")
 
 	exportConfigCmd.Flags().StringP("dataId", "d", "", "指定要导出的配置ID")
 	exportConfigCmd.Flags().StringP("group", "g", "", "指定要导出的配置分组")
 
 	importConfigCmd.Flags().StringP("file", "f", "", "指定要导入的配置文件")
+	importConfigCmd.Flags().Bool("skip-preflight", false, "跳过权限预检")
+	importConfigCmd.Flags().Bool("no-validate", false, "不检查配置的语法和 schema")
 
 	listConfigCmd.Flags().Int("page", 1, "页码")
 	listConfigCmd.Flags().Int("size", 20, "每页大小")
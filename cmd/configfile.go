@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// 配置文件路径，未指定 --config 且没有读取到配置文件时使用 $HOME/.nacos-cli.yaml
+func configFilePath() (string, error) {
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configFile = filepath.Join(home, ".nacos-cli.yaml")
+	}
+	return configFile, nil
+}
+
+// 读取配置文件中的原始设置。与 viper.AllSettings 不同，不包含命令行参数和环境变量的值
+func readConfigFile() (map[string]interface{}, error) {
+	configFile, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string]interface{})
+	data, err := os.ReadFile(configFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+	if settings == nil {
+		settings = make(map[string]interface{})
+	}
+	return settings, nil
+}
+
+// 修改配置文件中的设置并原子地写回，只写入文件中原有的和 update 修改的键，
+// 避免把 --server、--password 等命令行参数的值持久化。返回配置文件路径
+func updateConfigFile(update func(settings map[string]interface{})) (string, error) {
+	configFile, err := configFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	settings, err := readConfigFile()
+	if err != nil {
+		return "", err
+	}
+	update(settings)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(settings); err != nil {
+		return "", err
+	}
+	data := buf.Bytes()
+
+	tmp, err := os.CreateTemp(filepath.Dir(configFile), ".nacos-cli-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("保存配置失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("保存配置失败: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("保存配置失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("保存配置失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), configFile); err != nil {
+		return "", fmt.Errorf("保存配置失败: %w", err)
+	}
+
+	// 同步到 viper，使本次运行中后续读取到最新的值
+	for key, value := range settings {
+		viper.Set(key, value)
+	}
+	return configFile, nil
+}
@@ -0,0 +1,505 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"nacos-cli/pkg/nacos"
+	"nacos-cli/pkg/printer"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// 通过 --context 指定的上下文
+var contextName string
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "上下文管理",
+	Long: `管理多个Nacos集群的连接设置。每个上下文保存服务器地址、用户名、鉴权方式、命名空间和TLS设置，
+使用 'context use' 切换当前上下文，或使用 --context 临时指定。`,
+}
+
+var addContextCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "添加上下文",
+	Long: `添加或覆盖一个上下文，服务器地址、用户名和命名空间使用全局参数 --server、--username、--namespace 指定。
+密码不保存在上下文中，切换到该上下文后运行 'nacos-cli user set' 保存到凭据存储。`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationIgnoreContextErr: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		server, _ := cmd.Flags().GetString("server")
+		if server == "" {
+			return fmt.Errorf("必须使用 --server 指定服务器地址")
+		}
+		if !strings.HasPrefix(server, "http://") && !strings.HasPrefix(server, "https://") {
+			return fmt.Errorf("服务器地址缺少协议前缀，请使用 http:// 或 https://")
+		}
+
+		ctx := map[string]interface{}{"server": server}
+		if username, _ := cmd.Flags().GetString("username"); username != "" {
+			ctx["username"] = username
+		}
+		if namespace, _ := cmd.Flags().GetString("namespace"); namespace != "" {
+			ctx["namespace"] = namespace
+		}
+
+		auth := make(map[string]interface{})
+		method, _ := cmd.Flags().GetString("auth-method")
+		switch method {
+		case "":
+		case authPassword, authAccessKey, authExec, authNone:
+			auth["method"] = method
+		default:
+			return fmt.Errorf("不支持的鉴权方式: %s", method)
+		}
+		if accessKey, _ := cmd.Flags().GetString("access-key"); accessKey != "" {
+			auth["accessKey"] = accessKey
+		}
+		if len(auth) > 0 {
+			ctx["auth"] = auth
+		}
+
+		tls := make(map[string]interface{})
+		for flag, key := range map[string]string{
+			"ca-file":         "caFile",
+			"cert-file":       "certFile",
+			"key-file":        "keyFile",
+			"tls-server-name": "serverName",
+		} {
+			if value, _ := cmd.Flags().GetString(flag); value != "" {
+				tls[key] = value
+			}
+		}
+		if insecure, _ := cmd.Flags().GetBool("insecure-skip-tls-verify"); insecure {
+			tls["insecureSkipVerify"] = true
+		}
+		if len(tls) > 0 {
+			ctx["tls"] = tls
+		}
+
+		use, _ := cmd.Flags().GetBool("use")
+		configFile, err := updateConfigFile(func(settings map[string]interface{}) {
+			childMap(settings, "contexts")[name] = ctx
+			if use {
+				settings["currentContext"] = name
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("上下文 %s 已保存到 %s\n", name, configFile)
+		if use {
+			fmt.Printf("已切换到上下文: %s\n", name)
+		}
+		return nil
+	},
+}
+
+var useContextCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "切换当前上下文",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if _, err := readContext(name); err != nil {
+			return err
+		}
+
+		if _, err := updateConfigFile(func(settings map[string]interface{}) {
+			settings["currentContext"] = name
+		}); err != nil {
+			return err
+		}
+
+		fmt.Printf("已切换到上下文: %s\n", name)
+		return nil
+	},
+}
+
+var listContextCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "列出所有上下文",
+	Annotations: map[string]string{annotationIgnoreContextErr: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		contexts, _ := settings["contexts"].(map[string]interface{})
+
+		names := make([]string, 0, len(contexts))
+		for name := range contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		type contextInfo struct {
+			Name       string `json:"name"`
+			Current    bool   `json:"current"`
+			Server     string `json:"server"`
+			Username   string `json:"username,omitempty"`
+			Namespace  string `json:"namespace,omitempty"`
+			AuthMethod string `json:"authMethod"`
+		}
+
+		current := activeContext()
+		infos := make([]contextInfo, 0, len(names))
+		table := printer.NewTable("当前", "名称", "服务器", "命名空间", "鉴权方式", "用户名*")
+		for _, name := range names {
+			ctx, _ := contexts[name].(map[string]interface{})
+			info := contextInfo{
+				Name:       name,
+				Current:    name == current,
+				Server:     stringValue(ctx["server"]),
+				Username:   stringValue(ctx["username"]),
+				Namespace:  stringValue(ctx["namespace"]),
+				AuthMethod: stringValue(childMap(ctx, "auth")["method"]),
+			}
+			if info.AuthMethod == "" {
+				info.AuthMethod = authPassword
+			}
+			infos = append(infos, info)
+
+			marker := ""
+			if info.Current {
+				marker = "*"
+			}
+			table.AddRow(marker, info.Name, info.Server, info.Namespace, info.AuthMethod, info.Username)
+		}
+		return printOutput(&printer.Output{
+			Object: infos,
+			Table:  table,
+			Names:  names,
+			Empty:  "没有上下文，使用 'nacos-cli context add <name> --server <url>' 添加",
+		})
+	},
+}
+
+var showContextCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "显示上下文设置",
+	Long:  `显示指定上下文的设置，省略名称时显示当前上下文。密码、secretKey、请求头等敏感值显示为 ******`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := activeContext()
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if name == "" {
+			fmt.Println("当前没有使用上下文，使用配置文件顶层的设置")
+			return nil
+		}
+
+		ctx, err := readContext(name)
+		if err != nil {
+			return err
+		}
+		ctx = redactContext(ctx)
+
+		return printOutput(&printer.Output{
+			Object: ctx,
+			Names:  []string{name},
+			Text: func(w io.Writer, wide bool) error {
+				data, err := yaml.Marshal(ctx)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "上下文: %s\n", name)
+				_, err = w.Write(data)
+				return err
+			},
+		})
+	},
+}
+
+var deleteContextCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "删除上下文",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if _, err := readContext(name); err != nil {
+			return err
+		}
+
+		if _, err := updateConfigFile(func(settings map[string]interface{}) {
+			delete(childMap(settings, "contexts"), name)
+			if settings["currentContext"] == name {
+				delete(settings, "currentContext")
+			}
+		}); err != nil {
+			return err
+		}
+
+		fmt.Printf("上下文 %s 已删除\n", name)
+		return nil
+	},
+}
+
+var renameContextCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "重命名上下文",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldName, newName := args[0], args[1]
+		ctx, err := readContext(oldName)
+		if err != nil {
+			return err
+		}
+		if _, err := readContext(newName); err == nil {
+			return fmt.Errorf("上下文 %s 已存在", newName)
+		}
+
+		if _, err := updateConfigFile(func(settings map[string]interface{}) {
+			contexts := childMap(settings, "contexts")
+			delete(contexts, oldName)
+			contexts[newName] = ctx
+			if settings["currentContext"] == oldName {
+				settings["currentContext"] = newName
+			}
+		}); err != nil {
+			return err
+		}
+
+		fmt.Printf("上下文 %s 已重命名为 %s\n", oldName, newName)
+		return nil
+	},
+}
+
+// 当前使用的上下文：--context 参数优先，其次为项目配置文件中的 context 和配置文件中的 currentContext，
+// 都没有时返回空
+func activeContext() string {
+	if contextName != "" {
+		return contextName
+	}
+	if name := stringValue(projectSettings["context"]); name != "" {
+		return name
+	}
+	settings, err := readConfigFile()
+	if err != nil {
+		return ""
+	}
+	return stringValue(settings["currentContext"])
+}
+
+// 读取配置文件中的上下文。viper 会把键转为小写，因此直接读取文件以保留上下文名称的大小写
+func readContext(name string) (map[string]interface{}, error) {
+	settings, err := readConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	contexts, _ := settings["contexts"].(map[string]interface{})
+	ctx, ok := contexts[name].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("上下文 %s 不存在，使用 'nacos-cli context list' 查看所有上下文", name)
+	}
+	return ctx, nil
+}
+
+// 显示时用于替换敏感值的掩码
+const maskedValue = "******"
+
+// 返回隐去敏感值的上下文副本：密码、secretKey、服务身份标识的值，
+// 以及请求头和 exec 插件环境变量的值都可能是凭据，只保留键
+func redactContext(ctx map[string]interface{}) map[string]interface{} {
+	result := copyMap(ctx)
+	if _, ok := result["password"]; ok {
+		result["password"] = maskedValue
+	}
+	if headers, ok := result["headers"].(map[string]interface{}); ok {
+		masked := make(map[string]interface{}, len(headers))
+		for name := range headers {
+			masked[name] = maskedValue
+		}
+		result["headers"] = masked
+	}
+
+	auth, ok := result["auth"].(map[string]interface{})
+	if !ok {
+		return result
+	}
+	auth = copyMap(auth)
+	result["auth"] = auth
+	if _, ok := auth["secretKey"]; ok {
+		auth["secretKey"] = maskedValue
+	}
+	if identity, ok := auth["identity"].(map[string]interface{}); ok {
+		identity = copyMap(identity)
+		if _, ok := identity["value"]; ok {
+			identity["value"] = maskedValue
+		}
+		auth["identity"] = identity
+	}
+	if exec, ok := auth["exec"].(map[string]interface{}); ok {
+		exec = copyMap(exec)
+		if env, ok := exec["env"].([]interface{}); ok {
+			masked := make([]interface{}, len(env))
+			for i, entry := range env {
+				name, _, _ := strings.Cut(fmt.Sprint(entry), "=")
+				masked[i] = name + "=" + maskedValue
+			}
+			exec["env"] = masked
+		}
+		auth["exec"] = exec
+	}
+	return result
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
+}
+
+// 连接和鉴权相关的设置。使用上下文时这些设置只从上下文读取，不继承配置文件顶层的设置，
+// 避免把顶层的密码、身份标识和请求头发送到其他集群
+var contextScopedKeys = []string{"server", "username", "password", "namespace", "auth", "headers", "tls"}
+
+// 将当前上下文的设置合并到配置文件顶层设置之上，命令行参数和环境变量仍然优先
+func applyContext() error {
+	name := activeContext()
+	if name == "" {
+		return nil
+	}
+	ctx, err := readContext(name)
+	if err != nil {
+		return err
+	}
+
+	settings, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	// viper 不支持删除设置，将顶层设置中的各个值置为 nil，viper 会将其视为未设置
+	cleared := make(map[string]interface{})
+	for _, key := range contextScopedKeys {
+		if value, ok := settings[key]; ok {
+			cleared[key] = clearedValue(value)
+		}
+	}
+	if err := viper.MergeConfigMap(cleared); err != nil {
+		return err
+	}
+	return viper.MergeConfigMap(ctx)
+}
+
+// 与 value 结构相同、所有值都为 nil 的设置。viper 合并时不会用 nil 覆盖映射，因此逐层置空
+func clearedValue(value interface{}) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	cleared := make(map[string]interface{}, len(m))
+	for key, child := range m {
+		cleared[key] = clearedValue(child)
+	}
+	return cleared
+}
+
+// 使用指定的上下文创建客户端，创建后恢复当前上下文的设置
+func createContextClient(name string) (*nacos.Client, error) {
+	saved := contextName
+	contextName = name
+	defer func() {
+		contextName = saved
+		reloadSettings()
+	}()
+	if err := reloadSettings(); err != nil {
+		return nil, err
+	}
+	return createClient(), nil
+}
+
+// 重新读取主配置文件并依次合并上下文和项目配置
+func reloadSettings() error {
+	// 主配置文件不存在时没有上下文可用，由 applyContext 报告错误
+	viper.ReadInConfig()
+	if err := applyContext(); err != nil {
+		return err
+	}
+	return applyProjectConfig()
+}
+
+// 修改当前上下文的设置，没有使用上下文时修改配置文件顶层的设置。返回配置文件路径
+func updateActiveSettings(update func(settings map[string]interface{})) (string, error) {
+	name := activeContext()
+	if name == "" {
+		return updateConfigFile(update)
+	}
+	if _, err := readContext(name); err != nil {
+		return "", err
+	}
+
+	var ctx map[string]interface{}
+	configFile, err := updateConfigFile(func(settings map[string]interface{}) {
+		ctx = childMap(childMap(settings, "contexts"), name)
+		update(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	// updateConfigFile 同步的是顶层设置，这里再用上下文的设置覆盖
+	for key, value := range ctx {
+		viper.Set(key, value)
+	}
+	return configFile, nil
+}
+
+// 当前上下文的TLS设置
+func tlsConfig() nacos.TLSConfig {
+	return nacos.TLSConfig{
+		CAFile:             viper.GetString("tls.caFile"),
+		CertFile:           viper.GetString("tls.certFile"),
+		KeyFile:            viper.GetString("tls.keyFile"),
+		ServerName:         viper.GetString("tls.serverName"),
+		InsecureSkipVerify: viper.GetBool("tls.insecureSkipVerify"),
+	}
+}
+
+// 返回 settings 中名为 key 的子映射，不存在时创建
+func childMap(settings map[string]interface{}, key string) map[string]interface{} {
+	child, ok := settings[key].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		if settings != nil {
+			settings[key] = child
+		}
+	}
+	return child
+}
+
+func stringValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func init() {
+	rootCmd.AddCommand(contextCmd)
+
+	contextCmd.AddCommand(addContextCmd)
+	contextCmd.AddCommand(useContextCmd)
+	contextCmd.AddCommand(listContextCmd)
+	contextCmd.AddCommand(showContextCmd)
+	contextCmd.AddCommand(deleteContextCmd)
+	contextCmd.AddCommand(renameContextCmd)
+
+	addContextCmd.Flags().String("auth-method", "", "鉴权方式 (password, aksk, exec, none)")
+	addContextCmd.Flags().String("access-key", "", "AccessKey，鉴权方式为 aksk 时使用")
+	addContextCmd.Flags().String("ca-file", "", "额外信任的CA证书文件")
+	addContextCmd.Flags().String("cert-file", "", "双向TLS的客户端证书文件")
+	addContextCmd.Flags().String("key-file", "", "双向TLS的客户端私钥文件")
+	addContextCmd.Flags().String("tls-server-name", "", "校验服务端证书时使用的主机名")
+	addContextCmd.Flags().Bool("insecure-skip-tls-verify", false, "不校验服务端证书（仅用于测试环境）")
+	addContextCmd.Flags().Bool("use", false, "添加后切换到该上下文")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactContext(t *testing.T) {
+	ctx := map[string]interface{}{
+		"server":   "http://nacos:8848",
+		"username": "admin",
+		"password": "secret",
+		"headers":  map[string]interface{}{"X-Token": "abc"},
+		"auth": map[string]interface{}{
+			"method":    "aksk",
+			"accessKey": "ak",
+			"secretKey": "sk",
+			"identity":  map[string]interface{}{"key": "serverIdentity", "value": "security"},
+			"exec": map[string]interface{}{
+				"command": "get-token",
+				"env":     []interface{}{"TOKEN=xyz", "DEBUG"},
+			},
+		},
+		"tls": map[string]interface{}{"keyFile": "/etc/nacos/key.pem"},
+	}
+	want := map[string]interface{}{
+		"server":   "http://nacos:8848",
+		"username": "admin",
+		"password": "******",
+		"headers":  map[string]interface{}{"X-Token": "******"},
+		"auth": map[string]interface{}{
+			"method":    "aksk",
+			"accessKey": "ak",
+			"secretKey": "******",
+			"identity":  map[string]interface{}{"key": "serverIdentity", "value": "******"},
+			"exec": map[string]interface{}{
+				"command": "get-token",
+				"env":     []interface{}{"TOKEN=******", "DEBUG=******"},
+			},
+		},
+		"tls": map[string]interface{}{"keyFile": "/etc/nacos/key.pem"},
+	}
+
+	if got := redactContext(ctx); !reflect.DeepEqual(got, want) {
+		t.Errorf("redactContext() = %v, want %v", got, want)
+	}
+	// 不修改原来的上下文
+	if ctx["password"] != "secret" || ctx["auth"].(map[string]interface{})["secretKey"] != "sk" {
+		t.Errorf("redactContext() modified its input: %v", ctx)
+	}
+}
+
+func TestRedactContextWithoutSecrets(t *testing.T) {
+	ctx := map[string]interface{}{"server": "http://nacos:8848", "auth": map[string]interface{}{"method": "none"}}
+	if got := redactContext(ctx); !reflect.DeepEqual(got, ctx) {
+		t.Errorf("redactContext() = %v, want %v", got, ctx)
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"nacos-cli/pkg/configtree"
+	"nacos-cli/pkg/nacos"
+
+	"github.com/spf13/cobra"
+)
+
+var convertConfigCmd = &cobra.Command{
+	Use:   "convert [dataId] [group] --to <type>",
+	Short: "转换配置格式",
+	Long: `在 properties、yaml、json 格式之间转换配置，默认输出转换后的内容。
+properties 的键按点和下标（如 servers[0].host）展开为嵌套的对象和数组，\uXXXX 等转义会被还原，
+转为 properties 时嵌套的键以点连接，数组下标写作 [n]。转换保留键的顺序，但不保留注释。
+使用 --publish 时将转换结果发布为同一分组中的新配置，配置ID默认替换为目标格式的扩展名；
+使用 --file 时转换本地文件。
+
+示例:
+  nacos-cli config convert app.properties --to yaml
+  nacos-cli config convert app.properties DEFAULT_GROUP --to yaml --publish
+  nacos-cli config convert app.properties --to yaml --new-data-id application.yml --publish
+  nacos-cli config convert --file ./app.properties --to yaml --output-file ./app.yaml`,
+	Args: cobra.RangeArgs(0, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		ascii, _ := cmd.Flags().GetBool("ascii")
+		options := configtree.ConvertOptions{ASCII: ascii}
+		if to == "" {
+			return fmt.Errorf("必须使用--to指定目标格式")
+		}
+
+		if file != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("--file 不能与配置ID同时使用")
+			}
+			if from == "" {
+				from = fileConfigType(file)
+			}
+			return convertFile(cmd, file, from, to, options)
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("必须提供配置ID或使用--file指定文件")
+		}
+
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return err
+		}
+		dataID := resolveDataID(args[0])
+		group := groupArg(args, 1)
+		config, err := client.GetConfigDetail(dataID, group)
+		if err != nil {
+			return fmt.Errorf("获取配置失败: %w", err)
+		}
+		if from == "" {
+			from = remoteConfigType(config, dataID)
+		}
+
+		converted, err := configtree.Convert(from, to, []byte(config.Content), options)
+		if err != nil {
+			return fmt.Errorf("转换配置 %s@%s 失败: %w", dataID, group, err)
+		}
+		if publish, _ := cmd.Flags().GetBool("publish"); !publish {
+			fmt.Print(string(converted))
+			return nil
+		}
+
+		newDataID, _ := cmd.Flags().GetString("new-data-id")
+		if newDataID == "" {
+			newDataID = convertedDataID(dataID, to)
+		}
+		newDataID = resolveDataID(newDataID)
+		target := &nacos.Config{
+			DataID:     newDataID,
+			Group:      group,
+			Content:    string(converted),
+			Type:       to,
+			AppName:    config.AppName,
+			Desc:       config.Desc,
+			ConfigTags: config.ConfigTags,
+		}
+		if err := validateBeforePublish(cmd, target); err != nil {
+			return err
+		}
+
+		// 目标配置已存在时确认后覆盖，并按读取时的MD5条件发布；不存在时发布前确认仍未被其他人创建
+		var casMd5 string
+		existing, err := client.GetConfigDetail(newDataID, group)
+		switch {
+		case err == nil:
+			if !confirmAction(cmd, fmt.Sprintf("配置 %s@%s 已存在，确定要覆盖吗？(y/N): ", newDataID, group)) {
+				fmt.Println("操作已取消")
+				return nil
+			}
+			casMd5 = configMd5(existing)
+		case !errors.Is(err, nacos.ErrConfigNotFound):
+			return fmt.Errorf("获取配置失败: %w", err)
+		}
+
+		err = publishIfUnchanged(client, target, casMd5)
+		if errors.Is(err, nacos.ErrConfigConflict) {
+			return fmt.Errorf("配置 %s@%s 在读取后已被其他人修改，未发布，请重新运行", newDataID, group)
+		}
+		if err != nil {
+			return fmt.Errorf("发布配置失败: %w", err)
+		}
+
+		fmt.Printf("配置 %s@%s 已转换为 %s 并发布为 %s@%s\n", dataID, group, to, newDataID, group)
+		if newDataID != dataID {
+			fmt.Printf("原配置未删除，确认应用已切换后可以使用 'nacos-cli config delete %s %s' 删除\n", dataID, group)
+		}
+		return nil
+	},
+}
+
+// 转换本地文件，默认输出到标准输出，指定 --output-file 时写入文件
+func convertFile(cmd *cobra.Command, file, from, to string, options configtree.ConvertOptions) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+	converted, err := configtree.Convert(from, to, content, options)
+	if err != nil {
+		return fmt.Errorf("转换 %s 失败: %w", file, err)
+	}
+
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	if outputFile == "" {
+		fmt.Print(string(converted))
+		return nil
+	}
+	if err := os.WriteFile(outputFile, converted, 0644); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	fmt.Printf("已将 %s 转换为 %s 并保存到 %s\n", file, to, outputFile)
+	return nil
+}
+
+// 转换后的配置ID：将扩展名替换为目标类型的扩展名，如 app.properties 转为 yaml 时为 app.yaml
+func convertedDataID(dataID, configType string) string {
+	ext := path.Ext(dataID)
+	if nacos.InferConfigType(dataID) == "text" {
+		ext = ""
+	}
+	return strings.TrimSuffix(dataID, ext) + "." + configType
+}
+
+func init() {
+	configCmd.AddCommand(convertConfigCmd)
+
+	convertConfigCmd.Flags().String("to", "", "目标格式 (properties, yaml, json)")
+	convertConfigCmd.Flags().String("from", "", "源格式，默认使用配置的类型或按扩展名推断")
+	convertConfigCmd.Flags().StringP("file", "f", "", "转换本地文件而不是服务端的配置")
+	convertConfigCmd.Flags().String("output-file", "", "转换本地文件时保存结果的文件，默认输出到标准输出")
+	convertConfigCmd.Flags().Bool("publish", false, "将转换结果发布为新配置")
+	convertConfigCmd.Flags().String("new-data-id", "", "发布的配置ID，默认替换为目标格式的扩展名")
+	convertConfigCmd.Flags().Bool("ascii", false, "转为 properties 时将非 ASCII 字符写作 \\uXXXX")
+	convertConfigCmd.Flags().BoolP("yes", "y", false, "覆盖已存在的配置时不再确认")
+	convertConfigCmd.Flags().Bool("no-validate", false, "不检查配置的语法和 schema")
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nacos-cli/pkg/credential"
+
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// 打开配置中指定的凭据存储（credentialStore: auto|keyring|file）。
+// 加密文件的口令从 NACOS_CLI_PASSPHRASE 环境变量读取，未设置时在终端提示输入
+func openCredentialStore() (credential.Store, error) {
+	path := viper.GetString("credentialFile")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".nacos-cli", "credentials.json")
+	}
+
+	return credential.Open(viper.GetString("credentialStore"), credential.Options{
+		FilePath: path,
+		Passphrase: func() (string, error) {
+			if passphrase := os.Getenv("NACOS_CLI_PASSPHRASE"); passphrase != "" {
+				return passphrase, nil
+			}
+			fd := int(os.Stdin.Fd())
+			if !term.IsTerminal(fd) {
+				return "", fmt.Errorf("无法提示输入凭据文件口令，请设置 NACOS_CLI_PASSPHRASE 环境变量")
+			}
+			return promptPassword(fd, "请输入凭据文件口令: ")
+		},
+	})
+}
+
+// 支持的鉴权方式（配置项 auth.method）
+const (
+	authPassword  = "password"
+	authAccessKey = "aksk"
+	authExec      = "exec"
+	authNone      = "none"
+)
+
+// 为 true 时不读取凭据存储。shell 补全时无法提示输入口令，密钥环等待解锁也会让补全一直卡住
+var skipCredentialStore bool
+
+// 确定登录密码，优先级依次为：--password 参数或配置文件中的明文密码、
+// NACOS_PASSWORD_FILE 指定的文件、凭据存储
+func resolvePassword(server, username string) string {
+	password := viper.GetString("password")
+
+	// 指定了 NACOS_PASSWORD_FILE 时从文件读取密码，便于在 CI 中通过挂载的密钥文件传入
+	if passwordFile := os.Getenv("NACOS_PASSWORD_FILE"); passwordFile != "" && !rootCmd.PersistentFlags().Changed("password") {
+		if data, err := os.ReadFile(passwordFile); err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 读取 NACOS_PASSWORD_FILE 失败: %v\n", err)
+		} else {
+			password = strings.TrimRight(string(data), "\r\n")
+		}
+	}
+
+	// 未通过参数或配置文件提供密码时，从凭据存储中读取
+	if password == "" && server != "" && username != "" {
+		password = readStoredSecret(server, username)
+	}
+	return password
+}
+
+// 确定 AccessKey 对应的 SecretKey，优先级依次为：NACOS_SECRET_KEY 环境变量、
+// 配置项 auth.secretKey、凭据存储
+func resolveSecretKey(server, accessKey string) string {
+	if secretKey := os.Getenv("NACOS_SECRET_KEY"); secretKey != "" {
+		return secretKey
+	}
+	if secretKey := viper.GetString("auth.secretKey"); secretKey != "" {
+		return secretKey
+	}
+	if server == "" || accessKey == "" {
+		return ""
+	}
+	return readStoredSecret(server, accessKeyAccount(accessKey))
+}
+
+// AccessKey 在凭据存储中的账号名，与用户名区分开
+func accessKeyAccount(accessKey string) string {
+	return "accessKey:" + accessKey
+}
+
+// 从凭据存储读取，失败时只输出警告
+func readStoredSecret(server, account string) string {
+	if skipCredentialStore {
+		return ""
+	}
+	store, err := openCredentialStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 打开凭据存储失败: %v\n", err)
+		return ""
+	}
+	secret, err := store.Get(server, account)
+	if err != nil && !errors.Is(err, credential.ErrNotFound) {
+		fmt.Fprintf(os.Stderr, "警告: 读取凭据失败: %v\n", err)
+	}
+	return secret
+}
+
+// 组装每个请求都附加的请求头：配置项 headers 中的任意请求头，
+// 以及 auth.identity 指定的服务身份标识（对应服务端的 nacos.core.auth.server.identity.key/value）
+func requestHeaders() http.Header {
+	header := http.Header{}
+	for name, value := range viper.GetStringMapString("headers") {
+		// 使用上下文时顶层的请求头被置空
+		if value != "" {
+			header.Set(name, value)
+		}
+	}
+
+	if key := viper.GetString("auth.identity.key"); key != "" {
+		value := os.Getenv("NACOS_SERVER_IDENTITY_VALUE")
+		if value == "" {
+			value = viper.GetString("auth.identity.value")
+		}
+		header.Set(key, value)
+	}
+	return header
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"nacos-cli/pkg/configtree"
+	"nacos-cli/pkg/diff"
+	"nacos-cli/pkg/nacos"
+
+	"github.com/spf13/cobra"
+)
+
+var diffConfigCmd = &cobra.Command{
+	Use:   "diff [dataId] [group]",
+	Short: "比较配置",
+	Long: `比较本地文件与服务端的配置，或比较同一配置在两个命名空间、两个上下文（集群）中的内容。
+默认输出统一格式（unified）的文本差异；使用 --semantic 时按 yaml、json、properties 解析后逐个键比较，
+忽略格式、注释和键的顺序。没有差异时退出码为0，有差异时为1，出错时为2。
+
+示例:
+  nacos-cli config diff app.yml --file ./app.yml
+  nacos-cli config diff app.yml DEFAULT_GROUP --from-namespace dev --to-namespace prod
+  nacos-cli config diff app.yml --from-context staging --to-context prod --semantic`,
+	Args:        cobra.RangeArgs(1, 2),
+	Annotations: map[string]string{annotationResultExitCode: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		fromNamespace, _ := cmd.Flags().GetString("from-namespace")
+		toNamespace, _ := cmd.Flags().GetString("to-namespace")
+		fromContext, _ := cmd.Flags().GetString("from-context")
+		toContext, _ := cmd.Flags().GetString("to-context")
+		semantic, _ := cmd.Flags().GetBool("semantic")
+		lines, _ := cmd.Flags().GetInt("unified")
+
+		// 另一方省略上下文时与比较的一方相同
+		if toContext == "" {
+			toContext = fromContext
+		}
+		if file != "" && (toNamespace != "" || toContext != fromContext) {
+			return fmt.Errorf("--file 不能与 --to-namespace、--to-context 同时使用")
+		}
+		if file == "" && toNamespace == fromNamespace && toContext == fromContext {
+			return fmt.Errorf("请使用 --file、--to-namespace 或 --to-context 指定比较对象")
+		}
+
+		dataID := resolveDataID(args[0])
+		group := groupArg(args, 1)
+
+		from, err := remoteDiffSide(fromContext, fromNamespace, dataID, group)
+		if err != nil {
+			return err
+		}
+		var to *diffSide
+		if file != "" {
+			to, err = fileDiffSide(file, from.configType)
+		} else {
+			to, err = remoteDiffSide(toContext, toNamespace, dataID, group)
+		}
+		if err != nil {
+			return err
+		}
+		if !from.exists && !to.exists {
+			return fmt.Errorf("配置 %s@%s 在两侧都不存在", dataID, group)
+		}
+
+		var output string
+		if semantic {
+			output, err = semanticDiff(from, to)
+			if err != nil {
+				return err
+			}
+		} else {
+			output = diff.Unified(from.label, to.label, from.content, to.content, lines)
+		}
+
+		if output == "" {
+			fmt.Println("配置相同")
+			return nil
+		}
+		fmt.Print(output)
+		return negativeResult(cmd)
+	},
+}
+
+// 参与比较的一方
+type diffSide struct {
+	label      string
+	content    string
+	configType string
+	exists     bool
+}
+
+// 从指定上下文和命名空间获取配置，上下文和命名空间为空时使用当前的设置。配置不存在时内容为空
+func remoteDiffSide(context, namespace, dataID, group string) (*diffSide, error) {
+	var client *nacos.Client
+	if context != "" {
+		var err error
+		if client, err = createContextClient(context); err != nil {
+			return nil, err
+		}
+	} else {
+		client = createClient()
+	}
+	if namespace != "" {
+		client.Namespace = namespace
+	}
+	if _, err := ensureLogin(client); err != nil {
+		return nil, err
+	}
+
+	side := &diffSide{
+		label:      remoteLabel(context, client.Namespace, dataID, group),
+		configType: nacos.InferConfigType(dataID),
+	}
+	config, err := client.GetConfigDetail(dataID, group)
+	if errors.Is(err, nacos.ErrConfigNotFound) {
+		side.label += " (不存在)"
+		return side, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("获取配置失败: %w", err)
+	}
+	side.content = config.Content
+	side.exists = true
+	if config.Type != "" {
+		side.configType = config.Type
+	}
+	return side, nil
+}
+
+// 读取本地文件，能从扩展名推断类型时按扩展名，否则与服务端配置的类型一致
+func fileDiffSide(file, defaultType string) (*diffSide, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	configType := nacos.InferConfigType(file)
+	if configType == "text" {
+		configType = defaultType
+	}
+	return &diffSide{label: file, content: string(data), configType: configType, exists: true}, nil
+}
+
+func remoteLabel(context, namespace, dataID, group string) string {
+	if namespace == "" {
+		namespace = "public"
+	}
+	label := fmt.Sprintf("%s/%s@%s", namespace, dataID, group)
+	if context != "" {
+		label = context + ":" + label
+	}
+	return label
+}
+
+// 解析两侧的配置后按键比较，不存在的一方视为没有任何键
+func semanticDiff(from, to *diffSide) (string, error) {
+	fromKeys, err := diffKeys(from)
+	if err != nil {
+		return "", err
+	}
+	toKeys, err := diffKeys(to)
+	if err != nil {
+		return "", err
+	}
+	return diff.FormatKeys(from.label, to.label, diff.Keys(fromKeys, toKeys)), nil
+}
+
+func diffKeys(side *diffSide) (map[string]string, error) {
+	if !side.exists {
+		return map[string]string{}, nil
+	}
+	if !configtree.Supported(side.configType) {
+		return nil, fmt.Errorf("%s 的类型为 %s，仅支持按语义比较 yaml、json 和 properties", side.label, side.configType)
+	}
+	tree, err := configtree.Parse(side.configType, []byte(side.content))
+	if err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", side.label, err)
+	}
+	return configtree.Flatten(tree), nil
+}
+
+func init() {
+	configCmd.AddCommand(diffConfigCmd)
+
+	diffConfigCmd.Flags().StringP("file", "f", "", "与本地文件比较")
+	diffConfigCmd.Flags().String("from-namespace", "", "比较的一方所在的命名空间 (默认: 当前命名空间)")
+	diffConfigCmd.Flags().String("to-namespace", "", "比较的另一方所在的命名空间")
+	diffConfigCmd.Flags().String("from-context", "", "比较的一方使用的上下文 (默认: 当前上下文)")
+	diffConfigCmd.Flags().String("to-context", "", "比较的另一方使用的上下文 (默认: 与 --from-context 相同)")
+	diffConfigCmd.Flags().Bool("semantic", false, "解析 yaml、json、properties 后按键比较，忽略格式和键的顺序")
+	diffConfigCmd.Flags().IntP("unified", "U", 3, "差异上下文的行数")
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"nacos-cli/pkg/diff"
+	"nacos-cli/pkg/nacos"
+
+	"github.com/spf13/cobra"
+)
+
+var editConfigCmd = &cobra.Command{
+	Use:   "edit [dataId] [group]",
+	Short: "在编辑器中修改配置",
+	Long: `获取配置内容，在 $EDITOR（未设置时使用 $VISUAL，默认为 vi）中打开临时文件进行编辑。
+保存并退出编辑器后按配置类型检查语法，有错误时可以重新编辑；随后显示修改的差异，确认后发布。
+发布时要求服务端配置的MD5与获取时一致，如果配置在编辑期间被其他人修改，则放弃发布并保留临时文件。`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return err
+		}
+
+		dataID := resolveDataID(args[0])
+		group := groupArg(args, 1)
+		config, err := client.GetConfigDetail(dataID, group)
+		if err != nil {
+			return fmt.Errorf("获取配置失败: %w", err)
+		}
+		configType := remoteConfigType(config, dataID)
+
+		file, err := os.CreateTemp("", "nacos-"+tempFileName(dataID)+"-*"+editExtension(configType))
+		if err != nil {
+			return fmt.Errorf("创建临时文件失败: %w", err)
+		}
+		tempFile := file.Name()
+		_, err = file.WriteString(config.Content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(tempFile)
+			return fmt.Errorf("写入临时文件失败: %w", err)
+		}
+
+		edited, err := editUntilValid(tempFile, dataID, group, configType)
+		if err != nil {
+			os.Remove(tempFile)
+			return err
+		}
+		if edited == config.Content {
+			os.Remove(tempFile)
+			fmt.Println("配置未修改")
+			return nil
+		}
+
+		fmt.Print(diff.Unified(dataID+" (远程)", dataID+" (修改后)", config.Content, edited, 3))
+		if !confirmAction(cmd, "确定要发布修改吗？(y/N): ") {
+			os.Remove(tempFile)
+			fmt.Println("操作已取消")
+			return nil
+		}
+
+		casMd5 := configMd5(config)
+		config.Content = edited
+		config.Type = configType
+		err = publishIfUnchanged(client, config, casMd5)
+		if errors.Is(err, nacos.ErrConfigConflict) {
+			return fmt.Errorf("配置 %s@%s 在编辑期间已被其他人修改，未发布。修改后的内容保存在 %s", dataID, group, tempFile)
+		}
+		if err != nil {
+			return fmt.Errorf("发布配置失败: %w，修改后的内容保存在 %s", err, tempFile)
+		}
+
+		os.Remove(tempFile)
+		fmt.Printf("配置 %s@%s 发布成功\n", dataID, group)
+		return nil
+	},
+}
+
+// 获取配置时的MD5，用于条件发布。服务端未返回时按内容计算
+func configMd5(config *nacos.Config) string {
+	if config.MD5 != "" {
+		return config.MD5
+	}
+	return fmt.Sprintf("%x", md5.Sum([]byte(config.Content)))
+}
+
+// 发布前重新读取配置，确认服务端配置的MD5仍为 casMd5（为空表示配置仍不存在）后按该MD5条件发布，
+// 否则返回 nacos.ErrConfigConflict。不支持条件发布的旧版本服务端会忽略 casMd5，因此先检查一次
+func publishIfUnchanged(client *nacos.Client, config *nacos.Config, casMd5 string) error {
+	current, err := client.GetConfigDetail(config.DataID, config.Group)
+	switch {
+	case errors.Is(err, nacos.ErrConfigNotFound):
+		if casMd5 != "" {
+			return nacos.ErrConfigConflict
+		}
+		return client.PublishConfig(config)
+	case err != nil:
+		return fmt.Errorf("获取配置失败: %w", err)
+	case casMd5 == "" || configMd5(current) != casMd5:
+		return nacos.ErrConfigConflict
+	}
+	return client.PublishConfigCas(config, casMd5)
+}
+
+// 打开编辑器编辑文件，语法或 schema 检查失败时询问是否重新编辑。返回编辑后的内容
+func editUntilValid(file, dataID, group, configType string) (string, error) {
+	for {
+		if err := runEditor(file); err != nil {
+			return "", err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("读取临时文件失败: %w", err)
+		}
+
+		err = checkConfig(dataID, group, configType, data)
+		if err == nil {
+			return string(data), nil
+		}
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Print("是否重新编辑？(Y/n): ")
+		var answer string
+		// 标准输入已关闭（如非交互环境）时无法再回答，按放弃处理，避免反复打开编辑器
+		_, err = fmt.Scanln(&answer)
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+		}
+		if errors.Is(err, io.EOF) || strings.ToLower(answer) == "n" {
+			return "", fmt.Errorf("配置未通过检查，已放弃修改")
+		}
+	}
+}
+
+// 使用 $EDITOR 或 $VISUAL 指定的编辑器打开文件，编辑器命令可以带参数
+func runEditor(file string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = os.Getenv("VISUAL")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	fields := strings.Fields(editor)
+	command := exec.Command(fields[0], append(fields[1:], file)...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		return fmt.Errorf("运行编辑器 %s 失败: %w", editor, err)
+	}
+	return nil
+}
+
+// 临时文件的扩展名，便于编辑器按类型高亮
+func editExtension(configType string) string {
+	switch configType {
+	case "yaml", "properties", "json", "xml", "html", "toml":
+		return "." + configType
+	default:
+		return ".txt"
+	}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// 去掉配置ID中不适合用作文件名的字符和扩展名
+func tempFileName(dataID string) string {
+	name := unsafeFileChars.ReplaceAllString(dataID, "_")
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func init() {
+	configCmd.AddCommand(editConfigCmd)
+
+	editConfigCmd.Flags().BoolP("yes", "y", false, "跳过确认，直接发布")
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"nacos-cli/pkg/nacos"
+
+	"github.com/spf13/cobra"
+)
+
+var instanceCmd = &cobra.Command{
+	Use:   "instance",
+	Short: "实例管理",
+	Long:  `管理Nacos服务实例`,
+}
+
+var instanceMetadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "实例元数据管理",
+	Long: `批量修改服务实例的元数据。使用 --selector 选择要修改的实例，
+条件可以是 ip、port、cluster 或元数据键，值支持通配符，如 --selector ip=10.0.*。
+执行前会列出受影响的实例并要求确认。`,
+}
+
+var setInstanceMetadataCmd = &cobra.Command{
+	Use:   "set [service] [key=value]...",
+	Short: "批量设置实例元数据",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		metadata, err := parseKeyValues(args[1:])
+		if err != nil {
+			return err
+		}
+
+		client, group, instances, err := selectInstances(cmd, args[0])
+		if err != nil {
+			return err
+		}
+
+		keys := sortedKeys(metadata)
+		fmt.Printf("以下 %d 个实例的元数据将被修改:\n", len(instances))
+		for _, inst := range instances {
+			var changes []string
+			for _, key := range keys {
+				old, ok := inst.Metadata[key]
+				if !ok {
+					old = "<无>"
+				}
+				changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, old, metadata[key]))
+			}
+			fmt.Printf("  %-22s %-12s %s\n", inst.Address(), inst.ClusterName, strings.Join(changes, ", "))
+		}
+
+		if !confirmAction(cmd, "确定要修改这些实例的元数据吗？(y/N): ") {
+			fmt.Println("操作已取消")
+			return nil
+		}
+
+		if err := client.UpdateInstanceMetadata(args[0], group, instances, metadata); err != nil {
+			return err
+		}
+
+		fmt.Printf("已更新 %d 个实例的元数据\n", len(instances))
+		return nil
+	},
+}
+
+var removeInstanceMetadataCmd = &cobra.Command{
+	Use:   "remove [service] [key]...",
+	Short: "批量删除实例元数据",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keys := args[1:]
+
+		client, group, instances, err := selectInstances(cmd, args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("以下 %d 个实例将删除元数据 %s:\n", len(instances), strings.Join(keys, ", "))
+		for _, inst := range instances {
+			var current []string
+			for _, key := range keys {
+				if value, ok := inst.Metadata[key]; ok {
+					current = append(current, fmt.Sprintf("%s=%s", key, value))
+				}
+			}
+			fmt.Printf("  %-22s %-12s %s\n", inst.Address(), inst.ClusterName, strings.Join(current, ", "))
+		}
+
+		if !confirmAction(cmd, "确定要删除这些实例的元数据吗？(y/N): ") {
+			fmt.Println("操作已取消")
+			return nil
+		}
+
+		if err := client.RemoveInstanceMetadata(args[0], group, instances, keys); err != nil {
+			return err
+		}
+
+		fmt.Printf("已删除 %d 个实例的元数据\n", len(instances))
+		return nil
+	},
+}
+
+// 登录并按 --selector 选出要操作的实例
+func selectInstances(cmd *cobra.Command, service string) (*nacos.Client, string, []nacos.Instance, error) {
+	client := createClient()
+	_, err := ensureLogin(client)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	group, _ := cmd.Flags().GetString("group")
+	selectors, _ := cmd.Flags().GetStringArray("selector")
+	match, err := parseKeyValues(selectors)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	instances, err := client.ListInstances(service, group, nil, false)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	instances = nacos.FilterInstances(instances, match)
+	if len(instances) == 0 {
+		return nil, "", nil, fmt.Errorf("服务 %s 中没有匹配的实例", service)
+	}
+
+	return client, group, instances, nil
+}
+
+// 请求用户确认，指定了 --yes 时直接通过
+func confirmAction(cmd *cobra.Command, prompt string) bool {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return true
+	}
+	fmt.Print(prompt)
+	var confirm string
+	fmt.Scanln(&confirm)
+	return strings.ToLower(confirm) == "y"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	rootCmd.AddCommand(instanceCmd)
+
+	instanceCmd.AddCommand(instanceMetadataCmd)
+	instanceMetadataCmd.AddCommand(setInstanceMetadataCmd)
+	instanceMetadataCmd.AddCommand(removeInstanceMetadataCmd)
+
+	instanceMetadataCmd.PersistentFlags().StringP("group", "g", "", "服务分组 (默认: DEFAULT_GROUP)")
+	instanceMetadataCmd.PersistentFlags().StringArrayP("selector", "l", nil, "选择实例的条件，格式 key=value，值支持通配符")
+	instanceMetadataCmd.PersistentFlags().BoolP("yes", "y", false, "跳过确认")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"nacos-cli/pkg/configtree"
+	"nacos-cli/pkg/diff"
+	"nacos-cli/pkg/nacos"
+
+	"github.com/spf13/cobra"
+)
+
+const keyPathHelp = `键路径以点分隔，数组下标写作 [n]，如 server.port、servers[0].host；键中包含点时写作 a\.b 或 ['a.b']。
+properties 配置的键不分段，路径即完整的键名，如 server.port。`
+
+var getKeyConfigCmd = &cobra.Command{
+	Use:   "get-key <dataId> <group> <path>",
+	Short: "读取配置中的一个键",
+	Long: `读取 yaml、json 或 properties 配置中一个键的值。标量直接输出其值，对象和数组按配置本身的格式输出。
+` + keyPathHelp,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return err
+		}
+
+		dataID := resolveDataID(args[0])
+		config, err := client.GetConfigDetail(dataID, groupArg(args, 1))
+		if err != nil {
+			return fmt.Errorf("获取配置失败: %w", err)
+		}
+
+		value, err := configtree.GetKey(remoteConfigType(config, dataID), []byte(config.Content), args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var setKeyConfigCmd = &cobra.Command{
+	Use:   "set-key <dataId> <group> <path> <value>",
+	Short: "修改配置中的一个键",
+	Long: `修改 yaml、json 或 properties 配置中一个键的值，不存在的键会被创建，数组下标等于数组长度时追加元素。
+值按 yaml（json 配置按 json）解析，如 8080 为数字、true 为布尔值、'"8080"' 为字符串，无法解析时作为字符串。
+yaml 配置的注释和键的顺序保持不变。发布时要求服务端配置的MD5与读取时一致，避免覆盖他人同时进行的修改。
+` + keyPathHelp,
+	Args: cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateConfigKey(cmd, args, func(configType string, content []byte) ([]byte, error) {
+			return configtree.SetKey(configType, content, args[2], args[3])
+		})
+	},
+}
+
+var unsetKeyConfigCmd = &cobra.Command{
+	Use:   "unset-key <dataId> <group> <path>",
+	Short: "删除配置中的一个键",
+	Long: `删除 yaml、json 或 properties 配置中的一个键或数组元素。发布时要求服务端配置的MD5与读取时一致。
+` + keyPathHelp,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return updateConfigKey(cmd, args, func(configType string, content []byte) ([]byte, error) {
+			return configtree.UnsetKey(configType, content, args[2])
+		})
+	},
+}
+
+// 读取配置，修改后以读取时的MD5条件发布。指定 --dry-run 时只显示差异
+func updateConfigKey(cmd *cobra.Command, args []string, update func(configType string, content []byte) ([]byte, error)) error {
+	client := createClient()
+	_, err := ensureLogin(client)
+	if err != nil {
+		return err
+	}
+
+	dataID := resolveDataID(args[0])
+	group := groupArg(args, 1)
+	config, err := client.GetConfigDetail(dataID, group)
+	if err != nil {
+		return fmt.Errorf("获取配置失败: %w", err)
+	}
+
+	configType := remoteConfigType(config, dataID)
+	data, err := update(configType, []byte(config.Content))
+	if err != nil {
+		return err
+	}
+	content := string(data)
+	if content == config.Content {
+		fmt.Println("配置未修改")
+		return nil
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Print(diff.Unified(dataID+" (远程)", dataID+" (修改后)", config.Content, content, 3))
+		return nil
+	}
+
+	casMd5 := configMd5(config)
+	config.Content = content
+	config.Type = configType
+	if err := validateBeforePublish(cmd, config); err != nil {
+		return err
+	}
+	if err := publishIfUnchanged(client, config, casMd5); err != nil {
+		if errors.Is(err, nacos.ErrConfigConflict) {
+			return fmt.Errorf("配置 %s@%s 在读取后已被其他人修改，未发布，请重新运行", dataID, group)
+		}
+		return fmt.Errorf("发布配置失败: %w", err)
+	}
+
+	fmt.Printf("配置 %s@%s 的 %s 已更新\n", dataID, group, args[2])
+	return nil
+}
+
+// 配置的类型，服务端未记录时按配置ID推断
+func remoteConfigType(config *nacos.Config, dataID string) string {
+	if config.Type != "" {
+		return config.Type
+	}
+	return nacos.InferConfigType(dataID)
+}
+
+func init() {
+	configCmd.AddCommand(getKeyConfigCmd)
+	configCmd.AddCommand(setKeyConfigCmd)
+	configCmd.AddCommand(unsetKeyConfigCmd)
+
+	setKeyConfigCmd.Flags().Bool("dry-run", false, "只显示修改后的差异，不发布")
+	unsetKeyConfigCmd.Flags().Bool("dry-run", false, "只显示修改后的差异，不发布")
+	setKeyConfigCmd.Flags().Bool("no-validate", false, "不检查配置的语法和 schema")
+	unsetKeyConfigCmd.Flags().Bool("no-validate", false, "不检查配置的语法和 schema")
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -18,7 +17,7 @@ var listNamespaceCmd = &cobra.Command{
 	Short: "列出所有命名空间",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		_, err := client.Login()
+		_, err := ensureLogin(client)
 		if err != nil {
 			return fmt.Errorf("登录失败: %w", err)
 		}
@@ -28,17 +27,7 @@ var listNamespaceCmd = &cobra.Command{
 			return fmt.Errorf("获取命名空间列表失败: %w", err)
 		}
 
-		if len(namespaces) == 0 {
-			fmt.Println("没有找到命名空间")
-			return nil
-		}
-
-		fmt.Printf("%-40s %-20s %-50s\n", "命名空间ID", "命名空间名称", "描述")
-		fmt.Println(strings.Repeat("-", 110))
-		for _, ns := range namespaces {
-			fmt.Printf("%-40s %-20s %-50s\n", ns.Namespace, ns.NamespaceShowName, ns.NamespaceDesc)
-		}
-		return nil
+		return printOutput(namespaceOutput(namespaces))
 	},
 }
 
@@ -48,7 +37,7 @@ var createNamespaceCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		_, err := client.Login()
+		_, err := ensureLogin(client)
 		if err != nil {
 			return fmt.Errorf("登录失败: %w", err)
 		}
@@ -67,12 +56,13 @@ var createNamespaceCmd = &cobra.Command{
 }
 
 var deleteNamespaceCmd = &cobra.Command{
-	Use:   "delete [namespace-id]",
-	Short: "删除命名空间",
-	Args:  cobra.ExactArgs(1),
+	Use:               "delete [namespace-id]",
+	Short:             "删除命名空间",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeNamespaces,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		_, err := client.Login()
+		_, err := ensureLogin(client)
 		if err != nil {
 			return fmt.Errorf("登录失败: %w", err)
 		}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"nacos-cli/pkg/nacos"
+	"nacos-cli/pkg/printer"
+)
+
+// 通过 --output 指定的输出格式
+var outputFormat string
+
+// 按 --output 指定的格式输出到标准输出
+func printOutput(out *printer.Output) error {
+	return printer.Print(os.Stdout, outputFormat, out)
+}
+
+// 输出格式是否为表格等面向人阅读的格式，此时可以输出额外的提示信息
+func humanOutput() bool {
+	return outputFormat == printer.FormatTable || outputFormat == printer.FormatWide
+}
+
+// 格式化服务端返回的毫秒时间戳
+func formatMillis(ms int64) string {
+	if ms == 0 {
+		return ""
+	}
+	return time.UnixMilli(ms).Format("2006-01-02 15:04:05")
+}
+
+func namespaceOutput(namespaces []nacos.Namespace) *printer.Output {
+	table := printer.NewTable("命名空间ID", "命名空间名称", "描述", "配置数*", "配额*")
+	for _, ns := range namespaces {
+		table.AddRow(ns.Namespace, ns.NamespaceShowName, ns.NamespaceDesc, ns.ConfigCount, ns.Quota)
+	}
+	return &printer.Output{Object: namespaces, Table: table, Empty: "没有找到命名空间"}
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nacos-cli/pkg/printer"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// 项目配置文件名，与用户主目录下的配置文件同名
+const projectConfigName = ".nacos-cli.yaml"
+
+// 默认的配置分组
+const defaultGroup = "DEFAULT_GROUP"
+
+// 项目配置文件中允许的设置。项目配置通常提交到代码仓库，因此不允许设置服务器地址和凭据
+var projectKeys = map[string]bool{
+	"namespace":    true,
+	"group":        true,
+	"context":      true,
+	"dataIdPrefix": true,
+	"schemas":      true,
+}
+
+var (
+	projectFile     string                 // 找到的项目配置文件路径，没有时为空
+	projectSettings map[string]interface{} // 项目配置文件中的设置
+)
+
+// 从当前目录向上查找项目配置文件，跳过已作为主配置文件读取的文件。查找在用户主目录停止，
+// 主目录下的同名文件是默认的主配置文件，即使 --config 指定了其他文件也不作为项目配置读取
+func findProjectConfig(dir, home, configFile string) string {
+	for {
+		if home != "" && sameFile(dir, home) {
+			return ""
+		}
+		candidate := filepath.Join(dir, projectConfigName)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() && !sameFile(candidate, configFile) {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func sameFile(a, b string) bool {
+	if b == "" {
+		return false
+	}
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
+
+// 查找并读取项目配置文件，忽略不支持的设置
+func loadProjectConfig() error {
+	configFile, err := configFilePath()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	home, _ := os.UserHomeDir()
+	file := findProjectConfig(cwd, home, configFile)
+	if file == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("读取项目配置文件失败: %w", err)
+	}
+	settings := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("解析项目配置文件 %s 失败: %w", file, err)
+	}
+
+	projectSettings = make(map[string]interface{})
+	for key, value := range settings {
+		if !projectKeys[key] {
+			fmt.Fprintf(os.Stderr, "警告: 项目配置文件 %s 中的设置 %s 不受支持，已忽略（仅支持 %s）\n", file, key, projectKeyNames())
+			continue
+		}
+		projectSettings[key] = value
+	}
+	projectFile = file
+	fmt.Fprintln(os.Stderr, "使用项目配置文件:", file)
+	return nil
+}
+
+// 将项目配置合并到主配置文件和上下文的设置之上，命令行参数和环境变量仍然优先
+func applyProjectConfig() error {
+	settings := make(map[string]interface{})
+	for key, value := range projectSettings {
+		// context 在读取主配置文件时处理，schemas 由 schemaRules 单独读取
+		if key != "context" && key != "schemas" {
+			settings[key] = value
+		}
+	}
+	if len(settings) == 0 {
+		return nil
+	}
+	return viper.MergeConfigMap(settings)
+}
+
+// 配置分组参数，省略时使用配置项 group，都没有时使用 DEFAULT_GROUP
+func groupArg(args []string, index int) string {
+	if len(args) > index && args[index] != "" {
+		return args[index]
+	}
+	if group := viper.GetString("group"); group != "" {
+		return group
+	}
+	return defaultGroup
+}
+
+// 为配置ID加上配置项 dataIdPrefix 指定的前缀，已有前缀时不重复添加
+func resolveDataID(dataID string) string {
+	prefix := viper.GetString("dataIdPrefix")
+	if prefix == "" || strings.HasPrefix(dataID, prefix) {
+		return dataID
+	}
+	return prefix + dataID
+}
+
+var whereConfigCmd = &cobra.Command{
+	Use:   "where",
+	Short: "显示各项设置的来源",
+	Long: `显示当前生效的上下文、服务器地址、命名空间、默认分组等设置，以及每项设置来自命令行参数、
+环境变量、项目配置文件、上下文还是主配置文件。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configFile, err := configFilePath()
+		if err != nil {
+			return err
+		}
+		settings, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		type settingInfo struct {
+			Key    string `json:"key"`
+			Value  string `json:"value"`
+			Source string `json:"source"`
+		}
+
+		name, source := contextSource(settings, configFile)
+		infos := []settingInfo{{Key: "context", Value: name, Source: source}}
+
+		var ctx map[string]interface{}
+		if name != "" {
+			ctx, _ = readContext(name)
+		}
+		keys := []string{"server", "username", "namespace", "group", "dataIdPrefix", "auth.method"}
+		for _, key := range keys {
+			value := viper.GetString(key)
+			source := settingSource(key, settings, configFile, name, ctx)
+			if value == "" {
+				switch key {
+				case "namespace":
+					value = "public"
+				case "group":
+					value = defaultGroup
+				case "auth.method":
+					value = authPassword
+				}
+			}
+			infos = append(infos, settingInfo{Key: key, Value: value, Source: source})
+		}
+
+		table := printer.NewTable("设置", "值", "来源")
+		for _, info := range infos {
+			table.AddRow(info.Key, displayValue(info.Value), info.Source)
+		}
+		return printOutput(&printer.Output{
+			Object: infos,
+			Table:  table,
+			Text: func(w io.Writer, wide bool) error {
+				fmt.Fprintf(w, "主配置文件: %s\n", configFile)
+				if projectFile != "" {
+					fmt.Fprintf(w, "项目配置文件: %s\n", projectFile)
+				} else {
+					fmt.Fprintln(w, "项目配置文件: (未找到)")
+				}
+				fmt.Fprintln(w)
+				return printer.Print(w, printer.FormatTable, &printer.Output{Table: table})
+			},
+		})
+	},
+}
+
+// 当前上下文及其来源
+func contextSource(settings map[string]interface{}, configFile string) (string, string) {
+	if contextName != "" {
+		return contextName, "命令行参数 --context"
+	}
+	if name := stringValue(projectSettings["context"]); name != "" {
+		return name, "项目配置 " + projectFile
+	}
+	if name := stringValue(settings["currentContext"]); name != "" {
+		return name, "配置文件 " + configFile + " (currentContext)"
+	}
+	return "", "未使用上下文"
+}
+
+// 设置的来源，优先级与读取设置时一致
+func settingSource(key string, settings map[string]interface{}, configFile, contextName string, ctx map[string]interface{}) string {
+	if flag := rootCmd.PersistentFlags().Lookup(key); flag != nil && flag.Changed {
+		return "命令行参数 --" + key
+	}
+	if env := strings.ToUpper(key); !strings.Contains(key, ".") {
+		if _, ok := os.LookupEnv(env); ok {
+			return "环境变量 " + env
+		}
+	}
+	if _, ok := projectSettings[key]; ok {
+		return "项目配置 " + projectFile
+	}
+	if _, ok := lookupSetting(ctx, key); ok {
+		return fmt.Sprintf("上下文 %s (%s)", contextName, configFile)
+	}
+	if _, ok := lookupSetting(settings, key); ok {
+		return "配置文件 " + configFile
+	}
+	return "默认值"
+}
+
+// 按以点分隔的键查找嵌套的设置
+func lookupSetting(settings map[string]interface{}, key string) (interface{}, bool) {
+	parts := strings.Split(key, ".")
+	current := settings
+	for i, part := range parts {
+		value, ok := current[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return value, true
+		}
+		if current, ok = value.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func displayValue(value string) string {
+	if value == "" {
+		return "(未设置)"
+	}
+	return value
+}
+
+// 列出项目配置文件支持的设置，用于错误提示
+func projectKeyNames() string {
+	names := make([]string, 0, len(projectKeys))
+	for key := range projectKeys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	configCmd.AddCommand(whereConfigCmd)
+}
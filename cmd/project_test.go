@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindProjectConfig(t *testing.T) {
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	repo := filepath.Join(home, "src", "repo")
+	sub := filepath.Join(repo, "service", "api")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(dir string) string {
+		path := filepath.Join(dir, projectConfigName)
+		if err := os.WriteFile(path, []byte("group: G\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	homeConfig := write(home)
+	other := filepath.Join(root, "other.yaml")
+
+	// 主目录下的配置文件即使不是 --config 指定的文件也不作为项目配置
+	if got := findProjectConfig(sub, home, other); got != "" {
+		t.Errorf("findProjectConfig() = %q, want no project config", got)
+	}
+	if got := findProjectConfig(sub, "", homeConfig); got != "" {
+		t.Errorf("findProjectConfig() = %q, want main config skipped", got)
+	}
+
+	repoConfig := write(repo)
+	if got := findProjectConfig(sub, home, other); got != repoConfig {
+		t.Errorf("findProjectConfig() = %q, want %q", got, repoConfig)
+	}
+	if got := findProjectConfig(repo, home, other); got != repoConfig {
+		t.Errorf("findProjectConfig() = %q, want %q", got, repoConfig)
+	}
+	// --config 指定的文件不会再作为项目配置读取
+	if got := findProjectConfig(sub, home, repoConfig); got != "" {
+		t.Errorf("findProjectConfig() = %q, want main config skipped", got)
+	}
+
+	// 不在主目录下的目录一直查找到根目录
+	outside := filepath.Join(root, "srv", "app")
+	if err := os.MkdirAll(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	rootConfig := write(filepath.Join(root, "srv"))
+	if got := findProjectConfig(outside, home, other); got != rootConfig {
+		t.Errorf("findProjectConfig() = %q, want %q", got, rootConfig)
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// 读取密码：指定了 --password-stdin 时从标准输入读取第一行，
+// 否则在终端上无回显提示输入，confirm 为 true 时要求输入两次
+func readPassword(cmd *cobra.Command, prompt string, confirm bool) (string, error) {
+	if fromStdin, _ := cmd.Flags().GetBool("password-stdin"); fromStdin {
+		return readPasswordFrom(os.Stdin)
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("标准输入不是终端，无法提示输入密码，请使用 --password-stdin")
+	}
+
+	password, err := promptPassword(fd, prompt)
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", fmt.Errorf("密码不能为空")
+	}
+
+	if confirm {
+		again, err := promptPassword(fd, "请再次输入密码: ")
+		if err != nil {
+			return "", err
+		}
+		if again != password {
+			return "", fmt.Errorf("两次输入的密码不一致")
+		}
+	}
+
+	return password, nil
+}
+
+func promptPassword(fd int, prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	data, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("读取密码失败: %w", err)
+	}
+	return string(data), nil
+}
+
+// 从输入中读取第一行作为密码，去掉行尾换行符
+func readPasswordFrom(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("读取密码失败: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("密码不能为空")
+	}
+	return password, nil
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+
+	"nacos-cli/pkg/nacos"
+	"nacos-cli/pkg/printer"
+
+	"github.com/spf13/cobra"
+)
+
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "角色管理",
+	Long:  `管理服务端用户与角色的绑定关系，需要管理员权限`,
+}
+
+var bindRoleCmd = &cobra.Command{
+	Use:   "bind [role] [username]",
+	Short: "将用户绑定到角色",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		if err := client.BindRole(args[0], args[1]); err != nil {
+			return err
+		}
+
+		fmt.Printf("用户 %s 已绑定到角色 %s\n", args[1], args[0])
+		return nil
+	},
+}
+
+var unbindRoleCmd = &cobra.Command{
+	Use:   "unbind [role] [username]",
+	Short: "解除用户与角色的绑定",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		if err := client.UnbindRole(args[0], args[1]); err != nil {
+			return err
+		}
+
+		fmt.Printf("用户 %s 已从角色 %s 解绑\n", args[1], args[0])
+		return nil
+	},
+}
+
+var listRoleCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出角色绑定",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		search, _ := cmd.Flags().GetString("search")
+		if search != "" {
+			roles, err := client.SearchRoles(search)
+			if err != nil {
+				return err
+			}
+			table := printer.NewTable("角色")
+			for _, role := range roles {
+				table.AddRow(role)
+			}
+			return printOutput(&printer.Output{Object: roles, Table: table, Empty: "没有找到角色"})
+		}
+
+		pageNo, _ := cmd.Flags().GetInt("page")
+		pageSize, _ := cmd.Flags().GetInt("size")
+		username, _ := cmd.Flags().GetString("user")
+		role, _ := cmd.Flags().GetString("role")
+
+		page, err := client.ListRoles(pageNo, pageSize, username, role)
+		if err != nil {
+			return err
+		}
+
+		table := printer.NewTable("角色", "用户名")
+		for _, binding := range page.PageItems {
+			table.AddRow(binding.Role, binding.Username)
+		}
+		return printOutput(&printer.Output{
+			Object: page.PageItems,
+			Table:  table,
+			Empty:  "没有找到角色",
+			Footer: fmt.Sprintf("共 %d 条，第 %d/%d 页", page.TotalCount, page.PageNumber, page.PagesAvailable),
+		})
+	},
+}
+
+var permissionCmd = &cobra.Command{
+	Use:   "permission",
+	Short: "权限管理",
+	Long: `管理角色对资源的权限，需要管理员权限。
+资源格式为 namespace[:group[:dataId]]，省略的部分表示全部，如 dev 等同于 dev:*:*；
+动作可以是 r（读）、w（写）或 rw（读写）。`,
+}
+
+var grantPermissionCmd = &cobra.Command{
+	Use:   "grant [role] [resource] [r|w|rw]",
+	Short: "授予权限",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resource, err := nacos.NormalizeResource(args[1])
+		if err != nil {
+			return err
+		}
+		if err := nacos.ValidateAction(args[2]); err != nil {
+			return err
+		}
+
+		client := createClient()
+		_, err = ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		if err := client.GrantPermission(args[0], resource, args[2]); err != nil {
+			return err
+		}
+
+		fmt.Printf("已授予角色 %s 对 %s 的 %s 权限\n", args[0], resource, args[2])
+		return nil
+	},
+}
+
+var revokePermissionCmd = &cobra.Command{
+	Use:   "revoke [role] [resource] [r|w|rw]",
+	Short: "收回权限",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resource, err := nacos.NormalizeResource(args[1])
+		if err != nil {
+			return err
+		}
+		if err := nacos.ValidateAction(args[2]); err != nil {
+			return err
+		}
+
+		client := createClient()
+		_, err = ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		if err := client.RevokePermission(args[0], resource, args[2]); err != nil {
+			return err
+		}
+
+		fmt.Printf("已收回角色 %s 对 %s 的 %s 权限\n", args[0], resource, args[2])
+		return nil
+	},
+}
+
+var listPermissionCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出权限",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		pageNo, _ := cmd.Flags().GetInt("page")
+		pageSize, _ := cmd.Flags().GetInt("size")
+		role, _ := cmd.Flags().GetString("role")
+
+		page, err := client.ListPermissions(pageNo, pageSize, role)
+		if err != nil {
+			return err
+		}
+
+		table := printer.NewTable("角色", "资源", "动作")
+		for _, p := range page.PageItems {
+			table.AddRow(p.Role, p.Resource, p.Action)
+		}
+		return printOutput(&printer.Output{
+			Object: page.PageItems,
+			Table:  table,
+			Empty:  "没有找到权限",
+			Footer: fmt.Sprintf("共 %d 条，第 %d/%d 页", page.TotalCount, page.PageNumber, page.PagesAvailable),
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(roleCmd)
+	rootCmd.AddCommand(permissionCmd)
+
+	roleCmd.AddCommand(bindRoleCmd)
+	roleCmd.AddCommand(unbindRoleCmd)
+	roleCmd.AddCommand(listRoleCmd)
+
+	permissionCmd.AddCommand(grantPermissionCmd)
+	permissionCmd.AddCommand(revokePermissionCmd)
+	permissionCmd.AddCommand(listPermissionCmd)
+
+	listRoleCmd.Flags().Int("page", 1, "页码")
+	listRoleCmd.Flags().Int("size", 20, "每页大小")
+	listRoleCmd.Flags().String("user", "", "按用户名过滤")
+	listRoleCmd.Flags().String("role", "", "按角色名过滤")
+	listRoleCmd.Flags().String("search", "", "按角色名前缀搜索，只输出角色名")
+
+	listPermissionCmd.Flags().Int("page", 1, "页码")
+	listPermissionCmd.Flags().Int("size", 20, "每页大小")
+	listPermissionCmd.Flags().String("role", "", "按角色名过滤")
+}
@@ -1,24 +1,60 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
+	"nacos-cli/pkg/printer"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-var cfgFile string
+var (
+	cfgFile string
+	debug   bool
+	// 读取当前上下文失败的错误，在命令运行前报告
+	contextErr error
+)
+
+// 带有该注解的命令在当前上下文不存在时仍然运行，如 context list 和 context add
+const annotationIgnoreContextErr = "ignoreContextErr"
+
+// 带有该注解的命令以退出码1表示否定的结果（如有差异），运行出错时退出码为2，与 diff(1) 一致
+const annotationResultExitCode = "resultExitCode"
+
+// 命令已输出否定的结果，以退出码1结束，不再输出错误信息
+var errNegativeResult = errors.New("否定的结果")
+
+// 返回 errNegativeResult，并关闭 cobra 对该错误的提示和用法输出
+func negativeResult(cmd *cobra.Command) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return errNegativeResult
+}
 
 var rootCmd = &cobra.Command{
 	Use:   "nacos-cli",
 	Short: "Nacos命令行工具",
 	Long:  `一个用于管理Nacos配置、用户和工作空间的命令行工具`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if contextErr != nil && cmd.Annotations[annotationIgnoreContextErr] == "" {
+			return contextErr
+		}
+		return printer.ValidateFormat(outputFormat)
+	},
 }
 
 func Execute() {
-	err := rootCmd.Execute()
-	if err != nil {
+	cmd, err := rootCmd.ExecuteC()
+	switch {
+	case err == nil:
+	case errors.Is(err, errNegativeResult):
+		os.Exit(1)
+	case cmd != nil && cmd.Annotations[annotationResultExitCode] != "":
+		os.Exit(2)
+	default:
 		os.Exit(1)
 	}
 }
@@ -28,8 +64,11 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "配置文件路径 (默认: $HOME/.nacos-cli.yaml)")
 	rootCmd.PersistentFlags().String("server", "", "Nacos服务器地址")
 	rootCmd.PersistentFlags().String("username", "", "用户名")
-	rootCmd.PersistentFlags().String("password", "", "密码")
+	rootCmd.PersistentFlags().String("password", "", "密码（会出现在 shell 历史和进程列表中，建议使用 NACOS_PASSWORD_FILE）")
 	rootCmd.PersistentFlags().String("namespace", "", "命名空间")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "使用的上下文 (默认: 配置文件中的 currentContext)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", printer.FormatTable, "输出格式 (table, wide, json, yaml, name, csv, template=..., jsonpath=...)")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "输出调试日志（令牌和密码会被隐藏）")
 
 	viper.BindPFlag("server", rootCmd.PersistentFlags().Lookup("server"))
 	viper.BindPFlag("username", rootCmd.PersistentFlags().Lookup("username"))
@@ -37,6 +76,8 @@ func init() {
 	viper.BindPFlag("namespace", rootCmd.PersistentFlags().Lookup("namespace"))
 }
 
+// 读取配置：主配置文件（--config 或 $HOME/.nacos-cli.yaml）、当前上下文、从当前目录向上找到的项目配置文件依次覆盖，
+// 命令行参数和环境变量优先于所有配置文件
 func initConfig() {
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
@@ -53,4 +94,8 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintln(os.Stderr, "使用配置文件:", viper.ConfigFileUsed())
 	}
+
+	cobra.CheckErr(loadProjectConfig())
+	contextErr = applyContext()
+	cobra.CheckErr(applyProjectConfig())
 }
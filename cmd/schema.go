@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"nacos-cli/pkg/configtree"
+	"nacos-cli/pkg/schema"
+	"nacos-cli/pkg/validate"
+)
+
+// schema 注册表中的一项：配置ID和分组匹配时按 schema 文件检查配置
+type schemaRule struct {
+	DataID string // 配置ID的通配符模式，如 order-service-*.yaml
+	Group  string // 分组的通配符模式，为空时匹配所有分组
+	Schema string // schema 文件的路径
+	Source string // 定义该项的配置文件
+}
+
+// 已读取的 schema 文件，按路径缓存
+var loadedSchemas = make(map[string]*schema.Schema)
+
+// 读取项目配置文件和主配置文件中的 schemas 设置。schema 文件的相对路径相对于定义它的配置文件所在的目录
+func schemaRules() ([]schemaRule, error) {
+	var rules []schemaRule
+	if projectFile != "" {
+		projectRules, err := parseSchemaRules(projectSettings["schemas"], projectFile)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, projectRules...)
+	}
+
+	configFile, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+	settings, err := readConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	configRules, err := parseSchemaRules(settings["schemas"], configFile)
+	if err != nil {
+		return nil, err
+	}
+	return append(rules, configRules...), nil
+}
+
+func parseSchemaRules(value interface{}, source string) ([]schemaRule, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s 中的 schemas 应为列表", source)
+	}
+
+	var rules []schemaRule
+	for i, item := range items {
+		entry, _ := item.(map[string]interface{})
+		rule := schemaRule{
+			DataID: stringValue(entry["dataId"]),
+			Group:  stringValue(entry["group"]),
+			Schema: stringValue(entry["schema"]),
+			Source: source,
+		}
+		if rule.DataID == "" || rule.Schema == "" {
+			return nil, fmt.Errorf("%s 中 schemas 的第 %d 项缺少 dataId 或 schema", source, i+1)
+		}
+		for _, pattern := range []string{rule.DataID, rule.Group} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("%s 中 schemas 的第 %d 项的模式 %s 无效", source, i+1, pattern)
+			}
+		}
+		if !filepath.IsAbs(rule.Schema) {
+			rule.Schema = filepath.Join(filepath.Dir(source), rule.Schema)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// 与配置ID和分组匹配的 schema 文件，group 为空时不比较分组
+func matchSchemas(dataID, group string) ([]string, error) {
+	rules, err := schemaRules()
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, rule := range rules {
+		if ok, _ := path.Match(rule.DataID, dataID); !ok {
+			continue
+		}
+		if rule.Group != "" && group != "" {
+			if ok, _ := path.Match(rule.Group, group); !ok {
+				continue
+			}
+		}
+		files = append(files, rule.Schema)
+	}
+	return files, nil
+}
+
+func loadSchema(file string) (*schema.Schema, error) {
+	if s, ok := loadedSchemas[file]; ok {
+		return s, nil
+	}
+	s, err := schema.Load(file)
+	if err != nil {
+		return nil, err
+	}
+	loadedSchemas[file] = s
+	return s, nil
+}
+
+// 按 schema 文件检查配置内容，返回所有 schema 的检查结果
+func checkSchemas(files []string, configType string, content []byte) error {
+	var errs []error
+	for _, file := range files {
+		if !configtree.Supported(configType) {
+			return fmt.Errorf("%s 类型的配置不支持按 schema 检查（schema %s）", configType, file)
+		}
+		s, err := loadSchema(file)
+		if err != nil {
+			return err
+		}
+		if err := s.ValidateContent(configType, content); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// 发布前的检查：按类型检查语法，再按注册表中匹配的 schema 检查内容
+func checkConfig(dataID, group, configType string, content []byte) error {
+	if err := validate.Syntax(configType, content); err != nil {
+		return err
+	}
+	files, err := matchSchemas(dataID, group)
+	if err != nil {
+		return err
+	}
+	return checkSchemas(files, configType, content)
+}
+
+// schema 检查结果中的所有违反的地方
+func schemaViolations(err error) []schema.Violation {
+	var violations []schema.Violation
+	for _, e := range unwrapAll(err) {
+		var validationErr *schema.ValidationError
+		if errors.As(e, &validationErr) {
+			violations = append(violations, validationErr.Violations...)
+		}
+	}
+	return violations
+}
+
+func unwrapAll(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	if err == nil {
+		return nil
+	}
+	return []error{err}
+}
+
+// 多行的错误信息缩进后续行，便于在列表中显示
+func indentError(err error) string {
+	return strings.ReplaceAll(err.Error(), "\n", "\n  ")
+}
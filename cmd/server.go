@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"nacos-cli/pkg/printer"
+
+	"github.com/spf13/cobra"
+)
+
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "服务端状态",
+	Long:  `查看Nacos服务端和集群节点的状态`,
+}
+
+var serverStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "查看服务端状态",
+	Long:  `显示服务端版本、运行模式、鉴权开关以及存活/就绪检查结果，这些接口无需登录`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		// 服务端异常时登录可能失败，状态接口不依赖登录，失败时仅提示
+		if _, err := ensureLogin(client); err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 登录失败，将以匿名方式查询: %v\n", err)
+		}
+
+		state, err := client.GetServerState()
+		if err != nil {
+			return err
+		}
+
+		status := struct {
+			Server    string `json:"server"`
+			Version   string `json:"version"`
+			Mode      string `json:"mode"`
+			Function  string `json:"functionMode"`
+			Auth      bool   `json:"authEnabled"`
+			Liveness  string `json:"liveness"`
+			Readiness string `json:"readiness"`
+		}{
+			Server:    client.ServerURL,
+			Version:   state.Version,
+			Mode:      state.StandaloneMode,
+			Function:  state.FunctionMode,
+			Auth:      state.AuthEnabled,
+			Liveness:  healthText(client.CheckLiveness()),
+			Readiness: healthText(client.CheckReadiness()),
+		}
+
+		table := printer.NewTable("服务器", "版本", "运行模式", "功能模式", "鉴权", "存活检查", "就绪检查")
+		table.AddRow(status.Server, status.Version, status.Mode, status.Function, status.Auth, status.Liveness, status.Readiness)
+		return printOutput(&printer.Output{
+			Object: status,
+			Table:  table,
+			Text: func(w io.Writer, wide bool) error {
+				return printer.PrintFields(w, []printer.Field{
+					{Name: "服务器", Value: status.Server},
+					{Name: "版本", Value: status.Version},
+					{Name: "运行模式", Value: status.Mode},
+					{Name: "功能模式", Value: status.Function},
+					{Name: "鉴权", Value: status.Auth},
+					{Name: "存活检查", Value: status.Liveness},
+					{Name: "就绪检查", Value: status.Readiness},
+				})
+			},
+		})
+	},
+}
+
+var serverMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "列出集群节点",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		members, err := client.ListClusterMembers()
+		if err != nil {
+			return err
+		}
+
+		table := printer.NewTable("地址", "状态", "版本", "失败次数")
+		for _, m := range members {
+			table.AddRow(m.Address, m.State, m.Version(), m.FailAccessCnt)
+		}
+		return printOutput(&printer.Output{
+			Object: members,
+			Table:  table,
+			Text: func(w io.Writer, wide bool) error {
+				if err := printer.Print(w, printer.FormatTable, &printer.Output{Table: table}); err != nil {
+					return err
+				}
+
+				// 输出各节点视角的 Raft 分组信息，便于发现 leader 不一致的问题
+				for _, m := range members {
+					groups := m.RaftGroups()
+					if len(groups) == 0 {
+						continue
+					}
+					fmt.Fprintf(w, "\n节点 %s 的 Raft 分组:\n", m.Address)
+					raft := printer.NewTable("分组", "Leader", "Term")
+					for _, g := range groups {
+						raft.AddRow(g.Group, g.Leader, g.Term)
+					}
+					if err := printer.Print(w, printer.FormatTable, &printer.Output{Table: raft}); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		})
+	},
+}
+
+var serverSwitchesCmd = &cobra.Command{
+	Use:   "switches",
+	Short: "命名模块开关",
+	Long:  `查看和修改命名模块的开关，如 pushEnabled、healthCheckEnabled、distroThreshold 等`,
+}
+
+var getSwitchesCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "查看开关配置",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		switches, err := client.GetSwitches()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 1 {
+			value, ok := switches[args[0]]
+			if !ok {
+				return fmt.Errorf("开关 %s 不存在", args[0])
+			}
+			switches = map[string]interface{}{args[0]: value}
+		}
+
+		keys := make([]string, 0, len(switches))
+		for key := range switches {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		table := printer.NewTable("开关", "值")
+		for _, key := range keys {
+			value := switches[key]
+			// 复杂类型以 JSON 形式显示
+			if _, ok := value.(string); !ok {
+				if data, err := json.Marshal(value); err == nil {
+					value = string(data)
+				}
+			}
+			table.AddRow(key, value)
+		}
+		return printOutput(&printer.Output{Object: switches, Table: table})
+	},
+}
+
+var setSwitchCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "修改开关配置",
+	Long:  `修改命名模块的开关，默认对整个集群生效，使用 --local-only 只修改当前节点`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		switches, err := client.GetSwitches()
+		if err != nil {
+			return err
+		}
+
+		current, ok := switches[args[0]]
+		if !ok {
+			return fmt.Errorf("开关 %s 不存在", args[0])
+		}
+
+		localOnly, _ := cmd.Flags().GetBool("local-only")
+		scope := "整个集群"
+		if localOnly {
+			scope = "当前节点"
+		}
+		fmt.Printf("开关 %s: %v -> %s (作用范围: %s)\n", args[0], current, args[1], scope)
+		if !confirmAction(cmd, "确定要修改该开关吗？(y/N): ") {
+			fmt.Println("操作已取消")
+			return nil
+		}
+
+		if err := client.UpdateSwitch(args[0], args[1], localOnly); err != nil {
+			return err
+		}
+
+		fmt.Printf("开关 %s 已设置为 %s\n", args[0], args[1])
+		return nil
+	},
+}
+
+var serverMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "查看命名模块指标",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		metrics, err := client.GetNamingMetrics()
+		if err != nil {
+			return err
+		}
+
+		table := printer.NewTable("状态", "服务数", "实例数", "订阅数", "负责的服务数*", "负责的实例数*",
+			"客户端数", "负责的客户端数*", "CPU", "负载", "内存")
+		table.AddRow(metrics.Status, metrics.ServiceCount, metrics.InstanceCount, metrics.SubscribeCount,
+			metrics.ResponsibleServiceCount, metrics.ResponsibleInstanceCount, metrics.ClientCount,
+			metrics.ResponsibleClientCount, fmt.Sprintf("%.2f", metrics.CPU), fmt.Sprintf("%.2f", metrics.Load),
+			fmt.Sprintf("%.2f", metrics.Mem))
+		return printOutput(&printer.Output{
+			Object: metrics,
+			Table:  table,
+			Text: func(w io.Writer, wide bool) error {
+				return printer.PrintFields(w, []printer.Field{
+					{Name: "状态", Value: metrics.Status},
+					{Name: "服务数", Value: metrics.ServiceCount},
+					{Name: "实例数", Value: metrics.InstanceCount},
+					{Name: "订阅数", Value: metrics.SubscribeCount},
+					{Name: "负责的服务数", Value: metrics.ResponsibleServiceCount},
+					{Name: "负责的实例数", Value: metrics.ResponsibleInstanceCount},
+					{Name: "客户端数", Value: metrics.ClientCount},
+					{Name: "负责的客户端数", Value: metrics.ResponsibleClientCount},
+					{Name: "CPU", Value: fmt.Sprintf("%.2f", metrics.CPU)},
+					{Name: "负载", Value: fmt.Sprintf("%.2f", metrics.Load)},
+					{Name: "内存", Value: fmt.Sprintf("%.2f", metrics.Mem)},
+				})
+			},
+		})
+	},
+}
+
+func healthText(err error) string {
+	if err != nil {
+		return "失败"
+	}
+	return "正常"
+}
+
+func init() {
+	rootCmd.AddCommand(serverCmd)
+
+	serverCmd.AddCommand(serverStatusCmd)
+	serverCmd.AddCommand(serverMembersCmd)
+	serverCmd.AddCommand(serverSwitchesCmd)
+	serverCmd.AddCommand(serverMetricsCmd)
+
+	serverSwitchesCmd.AddCommand(getSwitchesCmd)
+	serverSwitchesCmd.AddCommand(setSwitchCmd)
+
+	setSwitchCmd.Flags().Bool("local-only", false, "只修改当前节点，不同步到集群")
+	setSwitchCmd.Flags().BoolP("yes", "y", false, "跳过确认")
+}
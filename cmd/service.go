@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"nacos-cli/pkg/nacos"
+	"nacos-cli/pkg/printer"
+
+	"github.com/spf13/cobra"
+)
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "服务管理",
+	Long:  `查询Nacos注册的服务和实例`,
+}
+
+var resolveServiceCmd = &cobra.Command{
+	Use:   "resolve [service]",
+	Short: "解析服务的可用实例",
+	Long: `从服务的健康实例中选出一个地址，便于脚本和定时任务调用。
+默认按权重随机选择；指定 --cluster 时优先选择这些集群中的实例，
+指定 --metadata 时只在元数据匹配的实例中选择，使用 --all 输出所有可用实例。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := createClient()
+		_, err := ensureLogin(client)
+		if err != nil {
+			return err
+		}
+
+		group, _ := cmd.Flags().GetString("group")
+		clusters, _ := cmd.Flags().GetStringSlice("cluster")
+		metadata, _ := cmd.Flags().GetStringArray("metadata")
+		all, _ := cmd.Flags().GetBool("all")
+		format, _ := cmd.Flags().GetString("format")
+		strategy, _ := cmd.Flags().GetString("strategy")
+		scheme, _ := cmd.Flags().GetString("scheme")
+
+		match, err := parseKeyValues(metadata)
+		if err != nil {
+			return err
+		}
+
+		var selector nacos.Selector
+		switch strategy {
+		case "weighted":
+			selector = nacos.NewWeightedRandomSelector()
+		case "round-robin":
+			selector = &nacos.RoundRobinSelector{}
+		default:
+			return fmt.Errorf("不支持的选择策略: %s，可选值: weighted, round-robin", strategy)
+		}
+		selector = &nacos.ClusterPreferredSelector{Clusters: clusters, Next: selector}
+		if len(match) > 0 {
+			selector = &nacos.MetadataSelector{Match: match, Next: selector}
+		}
+
+		resolver := nacos.NewResolver(client, args[0], group, selector)
+		instances, err := resolver.Instances()
+		if err != nil {
+			return err
+		}
+
+		// --format json 是 -o json 的旧写法
+		if format == "json" && !cmd.Flags().Changed("output") {
+			outputFormat = printer.FormatJSON
+		} else if format != "host:port" && format != "url" {
+			return fmt.Errorf("不支持的输出格式: %s，可选值: host:port, url，结构化输出使用 -o json", format)
+		}
+
+		if all {
+			candidates := nacos.PreferClusters(nacos.FilterInstances(instances, match), clusters)
+			if len(candidates) == 0 {
+				return fmt.Errorf("服务 %s 没有可用的实例", args[0])
+			}
+			return printOutput(instancesOutput(candidates, candidates, format, scheme))
+		}
+
+		inst, err := selector.Select(instances)
+		if err != nil {
+			return fmt.Errorf("服务 %s 没有可用的实例", args[0])
+		}
+		return printOutput(instancesOutput(inst, []nacos.Instance{*inst}, format, scheme))
+	},
+}
+
+// 实例的输出。table 格式按 --format 每行输出一个地址便于脚本使用，wide 格式输出包含集群、权重和元数据的表格
+func instancesOutput(object interface{}, instances []nacos.Instance, format, scheme string) *printer.Output {
+	table := printer.NewTable("地址", "集群", "权重", "健康", "元数据")
+	var names []string
+	for _, inst := range instances {
+		address := inst.Address()
+		if format == "url" {
+			address = inst.URL(scheme)
+		}
+		names = append(names, address)
+		var metadata []string
+		for _, key := range sortedKeys(inst.Metadata) {
+			metadata = append(metadata, key+"="+inst.Metadata[key])
+		}
+		table.AddRow(address, inst.ClusterName, inst.Weight, inst.Healthy, strings.Join(metadata, ","))
+	}
+
+	out := &printer.Output{Object: object, Table: table, Names: names}
+	if outputFormat != printer.FormatWide {
+		out.Text = func(w io.Writer, wide bool) error {
+			for _, name := range names {
+				if _, err := fmt.Fprintln(w, name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return out
+}
+
+// 解析 key=value 形式的参数
+func parseKeyValues(items []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, item := range items {
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("参数格式不正确，应为 key=value: %s", item)
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
+func init() {
+	rootCmd.AddCommand(serviceCmd)
+
+	serviceCmd.AddCommand(resolveServiceCmd)
+
+	resolveServiceCmd.Flags().StringP("group", "g", "", "服务分组 (默认: DEFAULT_GROUP)")
+	resolveServiceCmd.Flags().StringSliceP("cluster", "c", nil, "优先选择的集群，可指定多个")
+	resolveServiceCmd.Flags().StringArrayP("metadata", "m", nil, "按元数据过滤实例，格式 key=value，值支持通配符")
+	resolveServiceCmd.Flags().Bool("all", false, "输出所有可用实例")
+	resolveServiceCmd.Flags().String("format", "host:port", "地址的格式 (host:port, url)，结构化输出使用 -o json")
+	resolveServiceCmd.Flags().String("strategy", "weighted", "选择策略 (weighted, round-robin)")
+	resolveServiceCmd.Flags().String("scheme", "http", "url 格式使用的协议")
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"nacos-cli/pkg/nacos"
+	"nacos-cli/pkg/tokencache"
+
+	"github.com/spf13/viper"
+)
+
+// 打开令牌缓存，默认位于 $HOME/.nacos-cli/tokens.json，可通过配置项 tokenCacheFile 修改
+func openTokenCache() (*tokencache.Cache, error) {
+	path := viper.GetString("tokenCacheFile")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, ".nacos-cli", "tokens.json")
+	}
+	return tokencache.New(path), nil
+}
+
+// 确保客户端已登录。优先使用缓存中该服务器和用户的有效令牌；
+// 没有时在文件锁内再检查一次缓存后登录，并发运行的多个进程只会有一个真正登录
+func ensureLogin(client *nacos.Client) (*nacos.LoginResponse, error) {
+	// 只有用户名密码登录和凭据插件会获取令牌，其他鉴权方式无需缓存
+	if !usesToken(client) {
+		return client.Login()
+	}
+
+	cache, err := openTokenCache()
+	if err != nil {
+		return client.Login()
+	}
+
+	if entry, ok, err := cache.Get(client.ServerURL, tokenAccount(client)); err == nil && ok {
+		return useCachedToken(client, entry), nil
+	}
+
+	unlock, err := cache.Lock()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "警告: %v\n", err)
+		return client.Login()
+	}
+	defer unlock()
+
+	// 等待锁期间其他进程可能已经登录
+	if entry, ok, err := cache.Get(client.ServerURL, tokenAccount(client)); err == nil && ok {
+		return useCachedToken(client, entry), nil
+	}
+
+	return loginAndCache(client, cache)
+}
+
+// 重新登录并写入缓存，调用方需持有缓存锁
+func loginAndCache(client *nacos.Client, cache *tokencache.Cache) (*nacos.LoginResponse, error) {
+	loginResp, err := client.Login()
+	if err != nil {
+		return nil, err
+	}
+
+	// 没有过期时间的令牌只在本次运行中使用
+	if client.TokenExpiry == 0 {
+		return loginResp, nil
+	}
+
+	entry := tokencache.Entry{
+		Token:       client.Token,
+		Expiry:      client.TokenExpiry,
+		GlobalAdmin: loginResp.GlobalAdmin,
+	}
+	if err := cache.Put(client.ServerURL, tokenAccount(client), entry); err != nil {
+		fmt.Fprintf(os.Stderr, "警告: 保存令牌失败: %v\n", err)
+	}
+	return loginResp, nil
+}
+
+func useCachedToken(client *nacos.Client, entry tokencache.Entry) *nacos.LoginResponse {
+	client.Token = entry.Token
+	client.TokenExpiry = entry.Expiry
+	return &nacos.LoginResponse{
+		AccessToken: entry.Token,
+		TokenTtl:    int(time.Until(time.Unix(entry.Expiry, 0)).Seconds()),
+		GlobalAdmin: entry.GlobalAdmin,
+	}
+}
+
+// 判断客户端的鉴权方式是否需要登录获取令牌
+func usesToken(client *nacos.Client) bool {
+	switch client.Auth.(type) {
+	case nil, *nacos.PasswordAuth, *nacos.ExecAuth:
+		return true
+	default:
+		return false
+	}
+}
+
+// 令牌在缓存中的账号名。凭据插件未配置用户名时按插件命令区分
+func tokenAccount(client *nacos.Client) string {
+	if auth, ok := client.Auth.(*nacos.ExecAuth); ok && client.Username == "" {
+		return "exec:" + auth.Command
+	}
+	return client.Username
+}
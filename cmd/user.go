@@ -2,9 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
+	"nacos-cli/pkg/printer"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -18,29 +23,44 @@ var userCmd = &cobra.Command{
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "登录验证",
+	Long: `登录并将令牌保存到令牌缓存（默认 $HOME/.nacos-cli/tokens.json）。
+令牌按服务器地址和用户名分别缓存，后续命令在令牌过期前直接使用缓存的令牌。`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := createClient()
-		// 使用下划线忽略不需要的返回值
-		_, err := client.Login()
-		if err != nil {
-			return fmt.Errorf("登录失败: %w", err)
-		}
-
-		// 保存token到配置文件
-		viper.Set("token", client.Token)
-		viper.Set("tokenExpiry", client.TokenExpiry)
-
-		configFile := viper.ConfigFileUsed()
-		if configFile == "" {
-			home, err := os.UserHomeDir()
+		if fromStdin, _ := cmd.Flags().GetBool("password-stdin"); fromStdin {
+			password, err := readPasswordFrom(os.Stdin)
 			if err != nil {
 				return err
 			}
-			configFile = home + "/.nacos-cli.yaml"
+			client.Password = password
+		}
+
+		if !usesToken(client) {
+			if _, err := client.Login(); err != nil {
+				return fmt.Errorf("登录失败: %w", err)
+			}
+			fmt.Printf("当前鉴权方式为 %s，请求时直接携带鉴权信息，无需登录\n", viper.GetString("auth.method"))
+			return nil
 		}
 
-		if err := viper.WriteConfigAs(configFile); err != nil {
-			return fmt.Errorf("保存token失败: %w", err)
+		cache, err := openTokenCache()
+		if err != nil {
+			return err
+		}
+		unlock, err := cache.Lock()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		// 显式登录时总是重新获取令牌
+		if _, err := loginAndCache(client, cache); err != nil {
+			return fmt.Errorf("登录失败: %w", err)
+		}
+
+		if client.TokenExpiry == 0 {
+			fmt.Println("登录成功! Token没有过期时间，不会被缓存")
+			return nil
 		}
 
 		// 计算token过期时间
@@ -54,27 +74,63 @@ var loginCmd = &cobra.Command{
 }
 
 var setUserCmd = &cobra.Command{
-	Use:   "set [username] [password]",
+	Use:   "set [username]",
 	Short: "设置用户凭据",
-	Args:  cobra.ExactArgs(2),
+	Long: `设置用户凭据。用户名保存在配置文件中，密码按服务器地址保存在凭据存储中，
+存储类型由配置项 credentialStore 决定：auto（默认，优先使用系统密钥环，不可用时使用加密文件）、
+keyring（系统密钥环）或 file（口令加密的文件，口令可通过 NACOS_CLI_PASSPHRASE 环境变量提供）。
+密码在终端上无回显输入，或使用 --password-stdin 从标准输入读取，或从 NACOS_PASSWORD_FILE 指定的文件读取。`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 2 {
+			return fmt.Errorf("不支持通过命令行参数传入密码（会出现在 shell 历史和进程列表中），请在提示时输入，或使用 --password-stdin、NACOS_PASSWORD_FILE")
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		viper.Set("username", args[0])
-		viper.Set("password", args[1])
+		server := viper.GetString("server")
+		if server == "" {
+			return fmt.Errorf("服务器地址未设置，请先运行 'nacos-cli user server <url>'")
+		}
 
-		configFile := viper.ConfigFileUsed()
-		if configFile == "" {
-			home, err := os.UserHomeDir()
+		var password string
+		fromStdin, _ := cmd.Flags().GetBool("password-stdin")
+		if passwordFile := os.Getenv("NACOS_PASSWORD_FILE"); passwordFile != "" && !fromStdin {
+			data, err := os.ReadFile(passwordFile)
+			if err != nil {
+				return fmt.Errorf("读取 NACOS_PASSWORD_FILE 失败: %w", err)
+			}
+			if password = strings.TrimRight(string(data), "\r\n"); password == "" {
+				return fmt.Errorf("NACOS_PASSWORD_FILE 中的密码为空")
+			}
+		} else {
+			var err error
+			password, err = readPassword(cmd, fmt.Sprintf("请输入用户 %s 的密码: ", args[0]), false)
 			if err != nil {
 				return err
 			}
-			configFile = home + "/.nacos-cli.yaml"
 		}
 
-		if err := viper.WriteConfigAs(configFile); err != nil {
-			return fmt.Errorf("保存配置失败: %w", err)
+		store, err := openCredentialStore()
+		if err != nil {
+			return err
+		}
+		if err := store.Set(server, args[0], password); err != nil {
+			return fmt.Errorf("保存密码失败: %w", err)
+		}
+
+		configFile, err := updateActiveSettings(func(settings map[string]interface{}) {
+			settings["username"] = args[0]
+			delete(settings, "password")
+			// 之前切换到其他鉴权方式时，恢复为用户名密码登录
+			if auth, ok := settings["auth"].(map[string]interface{}); ok {
+				auth["method"] = authPassword
+			}
+		})
+		if err != nil {
+			return err
 		}
 
-		fmt.Printf("用户凭据已保存到 %s\n", configFile)
+		fmt.Printf("用户名已保存到 %s，密码已保存到%s\n", configFile, store.Name())
 		return nil
 	},
 }
@@ -91,14 +147,65 @@ var showUserCmd = &cobra.Command{
 		// 创建密码掩码
 		maskedPassword := ""
 		if len(password) > 0 {
-			maskedPassword = password[:1] + "****"
+			maskedPassword = "****** (明文保存，建议运行 'nacos-cli user migrate-credentials')"
+		} else if username != "" {
+			if store, err := openCredentialStore(); err == nil {
+				if _, err := store.Get(server, username); err == nil {
+					maskedPassword = "已保存在" + store.Name()
+				}
+			}
 		}
 
-		fmt.Printf("服务器: %s\n", server)
-		fmt.Printf("用户名: %s\n", username)
-		fmt.Printf("密码: %s\n", maskedPassword)
-		fmt.Printf("命名空间: %s\n", namespace)
-		return nil
+		method := viper.GetString("auth.method")
+		if method == "" {
+			method = authPassword
+		}
+
+		info := struct {
+			Context    string `json:"context,omitempty"`
+			Server     string `json:"server"`
+			AuthMethod string `json:"authMethod"`
+			AccessKey  string `json:"accessKey,omitempty"`
+			Username   string `json:"username,omitempty"`
+			Password   string `json:"password,omitempty"`
+			Namespace  string `json:"namespace"`
+		}{
+			Context:    activeContext(),
+			Server:     server,
+			AuthMethod: method,
+			Namespace:  namespace,
+		}
+		if method == authAccessKey {
+			info.AccessKey = viper.GetString("auth.accessKey")
+		} else {
+			info.Username = username
+			info.Password = maskedPassword
+		}
+
+		return printOutput(&printer.Output{
+			Object: info,
+			Names:  []string{info.Username},
+			Text: func(w io.Writer, wide bool) error {
+				var fields []printer.Field
+				if info.Context != "" {
+					fields = append(fields, printer.Field{Name: "上下文", Value: info.Context})
+				}
+				fields = append(fields,
+					printer.Field{Name: "服务器", Value: info.Server},
+					printer.Field{Name: "鉴权方式", Value: info.AuthMethod},
+				)
+				if method == authAccessKey {
+					fields = append(fields, printer.Field{Name: "AccessKey", Value: info.AccessKey})
+				} else {
+					fields = append(fields,
+						printer.Field{Name: "用户名", Value: info.Username},
+						printer.Field{Name: "密码", Value: info.Password},
+					)
+				}
+				fields = append(fields, printer.Field{Name: "命名空间", Value: info.Namespace})
+				return printer.PrintFields(w, fields)
+			},
+		})
 	},
 }
 
@@ -106,23 +213,37 @@ var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "退出登录",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// 清除保存的token
-		viper.Set("token", "")
-		viper.Set("tokenExpiry", 0)
+		client := createClient()
 
-		configFile := viper.ConfigFileUsed()
-		if configFile == "" {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return err
-			}
-			configFile = home + "/.nacos-cli.yaml"
+		cache, err := openTokenCache()
+		if err != nil {
+			return err
+		}
+		unlock, err := cache.Lock()
+		if err != nil {
+			return err
 		}
+		defer unlock()
 
-		if err := viper.WriteConfigAs(configFile); err != nil {
+		// 清除保存的token
+		if err := cache.Delete(client.ServerURL, tokenAccount(client)); err != nil {
 			return fmt.Errorf("清除token失败: %w", err)
 		}
 
+		// 清除旧版本保存在配置文件中的token
+		settings, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+		if _, ok := settings["token"]; ok {
+			if _, err := updateConfigFile(func(settings map[string]interface{}) {
+				delete(settings, "token")
+				delete(settings, "tokenExpiry")
+			}); err != nil {
+				return fmt.Errorf("清除token失败: %w", err)
+			}
+		}
+
 		fmt.Println("已退出登录")
 		return nil
 	},
@@ -133,23 +254,170 @@ var setServerCmd = &cobra.Command{
 	Short: "设置服务器地址",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		viper.Set("server", args[0])
+		configFile, err := updateActiveSettings(func(settings map[string]interface{}) {
+			settings["server"] = args[0]
+		})
+		if err != nil {
+			return err
+		}
 
-		configFile := viper.ConfigFileUsed()
-		if configFile == "" {
-			home, err := os.UserHomeDir()
+		fmt.Printf("服务器地址已设置为: %s\n", args[0])
+		fmt.Printf("配置已保存到: %s\n", configFile)
+		return nil
+	},
+}
+
+var migrateCredentialsCmd = &cobra.Command{
+	Use:   "migrate-credentials",
+	Short: "将配置文件中的明文密码迁移到凭据存储",
+	Long: `将配置文件顶层和各个上下文中的明文密码迁移到凭据存储，并从配置文件中删除。
+缺少服务器地址或用户名的密码无法确定归属，会被跳过并列出。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := readConfigFile()
+		if err != nil {
+			return err
+		}
+
+		entries := plaintextPasswords(settings)
+		if len(entries) == 0 {
+			fmt.Println("配置文件中没有明文密码，无需迁移")
+			return nil
+		}
+
+		var migrated, skipped []passwordEntry
+		for _, entry := range entries {
+			if entry.server == "" || entry.username == "" {
+				skipped = append(skipped, entry)
+			} else {
+				migrated = append(migrated, entry)
+			}
+		}
+
+		if len(migrated) > 0 {
+			store, err := openCredentialStore()
 			if err != nil {
 				return err
 			}
-			configFile = home + "/.nacos-cli.yaml"
+			for _, entry := range migrated {
+				if err := store.Set(entry.server, entry.username, entry.password); err != nil {
+					return fmt.Errorf("保存%s中的密码失败: %w", entry.scope(), err)
+				}
+			}
+
+			configFile, err := updateConfigFile(func(settings map[string]interface{}) {
+				for _, entry := range migrated {
+					if entry.context == "" {
+						delete(settings, "password")
+					} else {
+						delete(childMap(childMap(settings, "contexts"), entry.context), "password")
+					}
+				}
+			})
+			if err != nil {
+				return err
+			}
+			for _, entry := range migrated {
+				fmt.Printf("%s: 用户 %s 的密码已迁移到%s\n", entry.scope(), entry.username, store.Name())
+			}
+			fmt.Printf("已从 %s 中删除迁移的密码\n", configFile)
 		}
 
-		if err := viper.WriteConfigAs(configFile); err != nil {
-			return fmt.Errorf("保存配置失败: %w", err)
+		for _, entry := range skipped {
+			fmt.Fprintf(os.Stderr, "%s: 缺少服务器地址或用户名，无法确定密码归属，已跳过\n", entry.scope())
+		}
+		if len(skipped) > 0 {
+			return fmt.Errorf("%d 个明文密码未迁移", len(skipped))
 		}
+		return nil
+	},
+}
 
-		fmt.Printf("服务器地址已设置为: %s\n", args[0])
-		fmt.Printf("配置已保存到: %s\n", configFile)
+// 配置文件中的一个明文密码，context 为空表示配置文件顶层
+type passwordEntry struct {
+	context, server, username, password string
+}
+
+func (e passwordEntry) scope() string {
+	if e.context == "" {
+		return "配置文件顶层"
+	}
+	return "上下文 " + e.context
+}
+
+// 按顶层、上下文名称的顺序列出配置文件中的明文密码。上下文不继承顶层的服务器地址和用户名
+func plaintextPasswords(settings map[string]interface{}) []passwordEntry {
+	var entries []passwordEntry
+	add := func(context string, scope map[string]interface{}) {
+		password, _ := scope["password"].(string)
+		if password == "" {
+			return
+		}
+		server, _ := scope["server"].(string)
+		username, _ := scope["username"].(string)
+		entries = append(entries, passwordEntry{context: context, server: server, username: username, password: password})
+	}
+
+	add("", settings)
+	contexts, _ := settings["contexts"].(map[string]interface{})
+	names := make([]string, 0, len(contexts))
+	for name := range contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ctx, ok := contexts[name].(map[string]interface{}); ok {
+			add(name, ctx)
+		}
+	}
+	return entries
+}
+
+var setAccessKeyCmd = &cobra.Command{
+	Use:   "set-access-key [accessKey]",
+	Short: "设置AccessKey/SecretKey签名鉴权",
+	Long: `为阿里云 MSE 等使用 AccessKey/SecretKey 签名鉴权的服务端设置凭据，并将鉴权方式切换为 aksk。
+SecretKey 在终端上无回显输入，或使用 --secret-key-stdin 从标准输入读取，保存在凭据存储中。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server := viper.GetString("server")
+		if server == "" {
+			return fmt.Errorf("服务器地址未设置，请先运行 'nacos-cli user server <url>'")
+		}
+
+		var secretKey string
+		var err error
+		if fromStdin, _ := cmd.Flags().GetBool("secret-key-stdin"); fromStdin {
+			secretKey, err = readPasswordFrom(os.Stdin)
+		} else {
+			secretKey, err = readPassword(cmd, "请输入SecretKey: ", false)
+		}
+		if err != nil {
+			return err
+		}
+
+		store, err := openCredentialStore()
+		if err != nil {
+			return err
+		}
+		if err := store.Set(server, accessKeyAccount(args[0]), secretKey); err != nil {
+			return fmt.Errorf("保存SecretKey失败: %w", err)
+		}
+
+		configFile, err := updateActiveSettings(func(settings map[string]interface{}) {
+			auth, _ := settings["auth"].(map[string]interface{})
+			if auth == nil {
+				auth = make(map[string]interface{})
+			}
+			auth["method"] = authAccessKey
+			auth["accessKey"] = args[0]
+			delete(auth, "secretKey")
+			settings["auth"] = auth
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("鉴权方式已切换为 aksk，AccessKey 已保存到 %s，SecretKey 已保存到%s\n", configFile, store.Name())
 		return nil
 	},
 }
@@ -162,4 +430,11 @@ func init() {
 	userCmd.AddCommand(setUserCmd)
 	userCmd.AddCommand(showUserCmd)
 	userCmd.AddCommand(setServerCmd)
+	userCmd.AddCommand(migrateCredentialsCmd)
+	userCmd.AddCommand(setAccessKeyCmd)
+
+	setAccessKeyCmd.Flags().Bool("secret-key-stdin", false, "从标准输入读取SecretKey")
+
+	setUserCmd.Flags().Bool("password-stdin", false, "从标准输入读取密码")
+	loginCmd.Flags().Bool("password-stdin", false, "从标准输入读取本次登录使用的密码")
 }
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nacos-cli/pkg/nacos"
+	"nacos-cli/pkg/printer"
+	"nacos-cli/pkg/schema"
+	"nacos-cli/pkg/validate"
+
+	"github.com/spf13/cobra"
+)
+
+var validateConfigCmd = &cobra.Command{
+	Use:   "validate <file|dir>...",
+	Short: "检查配置文件的语法和 schema",
+	Long: `按类型检查本地配置文件的语法，适用于在 CI 中发布前检查。指定目录时递归检查其中的所有文件。
+类型按文件扩展名推断（yaml、json、xml、properties、toml、html），'config export' 导出的 group@dataId 文件按 dataId 推断，
+无法推断类型的文件会被跳过，可以使用 --type 指定所有文件的类型。
+语法正确的文件再按 JSON Schema 检查：使用 --schema 指定时按指定的 schema 检查所有文件，
+否则按配置中 schemas 注册的规则匹配文件名对应的配置ID和分组。有文件未通过检查时退出码为1，出错（如文件不存在）时为2。`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{annotationResultExitCode: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configType, _ := cmd.Flags().GetString("type")
+		if configType != "" && !validate.Supported(configType) {
+			return fmt.Errorf("不支持检查 %s 类型的配置", configType)
+		}
+
+		var files []string
+		for _, arg := range args {
+			err := filepath.WalkDir(arg, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				// 跳过 .git 等隐藏目录
+				if entry.IsDir() && path != arg && strings.HasPrefix(entry.Name(), ".") {
+					return filepath.SkipDir
+				}
+				if !entry.IsDir() {
+					files = append(files, path)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("读取 %s 失败: %w", arg, err)
+			}
+		}
+
+		schemaFiles, _ := cmd.Flags().GetStringSlice("schema")
+
+		type fileResult struct {
+			File       string             `json:"file"`
+			Type       string             `json:"type"`
+			Status     string             `json:"status"`
+			Schemas    []string           `json:"schemas,omitempty"`
+			Error      string             `json:"error,omitempty"`
+			Violations []schema.Violation `json:"violations,omitempty"`
+		}
+
+		var results []fileResult
+		failed := 0
+		table := printer.NewTable("文件", "类型", "结果")
+		for _, file := range files {
+			result := fileResult{File: file, Type: configType, Status: "ok"}
+			if result.Type == "" {
+				result.Type = fileConfigType(file)
+			}
+			result.Schemas = schemaFiles
+			if len(schemaFiles) == 0 {
+				dataID, group := fileDataID(file)
+				matched, err := matchSchemas(dataID, group)
+				if err != nil {
+					return err
+				}
+				result.Schemas = matched
+			}
+
+			if !validate.Supported(result.Type) {
+				result.Status = "skipped"
+			} else if err := checkFile(file, result.Type, result.Schemas); err != nil {
+				result.Status, result.Error = "failed", err.Error()
+				result.Violations = schemaViolations(err)
+				failed++
+			}
+			results = append(results, result)
+
+			switch result.Status {
+			case "ok":
+				table.AddRow(file, result.Type, "通过")
+			case "skipped":
+				table.AddRow(file, result.Type, "跳过")
+			default:
+				table.AddRow(file, result.Type, result.Error)
+			}
+		}
+
+		if err := printOutput(&printer.Output{
+			Object: results,
+			Table:  table,
+			Text: func(w io.Writer, wide bool) error {
+				for _, result := range results {
+					switch result.Status {
+					case "ok":
+						fmt.Fprintf(w, "通过  %s\n", result.File)
+					case "skipped":
+						fmt.Fprintf(w, "跳过  %s（无法推断类型）\n", result.File)
+					default:
+						fmt.Fprintf(w, "失败  %s: %s\n", result.File, strings.ReplaceAll(result.Error, "\n", "\n      "))
+					}
+				}
+				return nil
+			},
+			Empty:  "没有找到文件",
+			Footer: fmt.Sprintf("共 %d 个文件，%d 个未通过检查", len(files), failed),
+		}); err != nil {
+			return err
+		}
+		if failed > 0 {
+			return negativeResult(cmd)
+		}
+		return nil
+	},
+}
+
+// 检查本地文件的语法，再按 schema 检查内容
+func checkFile(file, configType string, schemas []string) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if err := validate.Syntax(configType, content); err != nil {
+		return err
+	}
+	return checkSchemas(schemas, configType, content)
+}
+
+// 本地文件对应的配置ID和分组：'config export' 导出的文件名为 group@dataId，其他文件以文件名为配置ID，分组未知
+func fileDataID(file string) (string, string) {
+	name := filepath.Base(file)
+	if parts := strings.SplitN(name, "@", 2); len(parts) == 2 {
+		return parts[1], parts[0]
+	}
+	return name, ""
+}
+
+// 本地文件的配置类型：'config export' 导出的 group@dataId 文件按 dataId 推断，其他文件按扩展名推断
+func fileConfigType(file string) string {
+	dataID, _ := fileDataID(file)
+	return nacos.InferConfigType(dataID)
+}
+
+// 发布前按配置类型检查语法，并按注册表中匹配的 schema 检查内容，指定 --no-validate 时跳过
+func validateBeforePublish(cmd *cobra.Command, config *nacos.Config) error {
+	if noValidate, _ := cmd.Flags().GetBool("no-validate"); noValidate {
+		return nil
+	}
+	if err := checkConfig(config.DataID, config.Group, config.Type, []byte(config.Content)); err != nil {
+		return fmt.Errorf("配置 %s@%s 未通过检查，没有发布: %w（使用 --no-validate 跳过检查）", config.DataID, config.Group, err)
+	}
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(validateConfigCmd)
+
+	validateConfigCmd.Flags().String("type", "", "所有文件的配置类型，默认按扩展名推断")
+	validateConfigCmd.Flags().StringSlice("schema", nil, "按指定的 schema 文件检查所有文件，默认使用配置中 schemas 注册的 schema")
+}
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"strings"
 
+	"nacos-cli/pkg/printer"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -16,26 +18,21 @@ var workspaceCmd = &cobra.Command{
 }
 
 var setWorkspaceCmd = &cobra.Command{
-	Use:   "set [namespace]",
-	Short: "设置当前工作空间",
-	Args:  cobra.ExactArgs(1),
+	Use:               "set [namespace]",
+	Short:             "设置当前工作空间",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeNamespaces,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		viper.Set("namespace", args[0])
-
-		configFile := viper.ConfigFileUsed()
-		if configFile == "" {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return err
-			}
-			configFile = home + "/.nacos-cli.yaml"
-		}
-
-		if err := viper.WriteConfigAs(configFile); err != nil {
-			return fmt.Errorf("保存配置失败: %w", err)
+		if _, err := updateActiveSettings(func(settings map[string]interface{}) {
+			settings["namespace"] = args[0]
+		}); err != nil {
+			return err
 		}
 
 		fmt.Printf("工作空间已设置为: %s\n", args[0])
+		if _, ok := projectSettings["namespace"]; ok {
+			fmt.Printf("注意: 当前目录下的项目配置文件 %s 设置了命名空间，会覆盖此设置\n", projectFile)
+		}
 		return nil
 	},
 }
@@ -44,13 +41,29 @@ var showWorkspaceCmd = &cobra.Command{
 	Use:   "show",
 	Short: "显示当前工作空间",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		namespace := viper.GetString("namespace")
-		if namespace == "" {
-			fmt.Println("当前工作空间: public (默认)")
-		} else {
-			fmt.Printf("当前工作空间: %s\n", namespace)
+		workspace := struct {
+			Context   string `json:"context,omitempty"`
+			Namespace string `json:"namespace"`
+		}{
+			Context:   activeContext(),
+			Namespace: viper.GetString("namespace"),
 		}
-		return nil
+
+		return printOutput(&printer.Output{
+			Object: workspace,
+			Names:  []string{workspace.Namespace},
+			Text: func(w io.Writer, wide bool) error {
+				if workspace.Context != "" {
+					fmt.Fprintf(w, "当前上下文: %s\n", workspace.Context)
+				}
+				if workspace.Namespace == "" {
+					fmt.Fprintln(w, "当前工作空间: public (默认)")
+				} else {
+					fmt.Fprintf(w, "当前工作空间: %s\n", workspace.Namespace)
+				}
+				return nil
+			},
+		})
 	},
 }
 
@@ -58,20 +71,12 @@ var clearWorkspaceCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "清除工作空间设置",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		viper.Set("namespace", "")
-
-		configFile := viper.ConfigFileUsed()
-		if configFile == "" {
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return err
-			}
-			configFile = home + "/.nacos-cli.yaml"
-		}
-
-		if err := viper.WriteConfigAs(configFile); err != nil {
-			return fmt.Errorf("保存配置失败: %w", err)
+		if _, err := updateActiveSettings(func(settings map[string]interface{}) {
+			delete(settings, "namespace")
+		}); err != nil {
+			return err
 		}
+		viper.Set("namespace", "")
 
 		fmt.Println("工作空间已清除，使用默认命名空间")
 		return nil
@@ -91,7 +96,7 @@ func init() {
 		Short: "列出所有命名空间",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client := createClient()
-			_, err := client.Login()
+			_, err := ensureLogin(client)
 			if err != nil {
 				return fmt.Errorf("登录失败: %w", err)
 			}
@@ -101,12 +106,7 @@ func init() {
 				return fmt.Errorf("获取命名空间列表失败: %w", err)
 			}
 
-			fmt.Printf("%-20s %-30s %-40s\n", "命名空间ID", "名称", "描述")
-			fmt.Println(strings.Repeat("-", 90))
-			for _, ns := range namespaces {
-				fmt.Printf("%-20s %-30s %-40s\n", ns.Namespace, ns.NamespaceShowName, ns.NamespaceDesc)
-			}
-			return nil
+			return printOutput(namespaceOutput(namespaces))
 		},
 	}
 
@@ -116,7 +116,7 @@ func init() {
 		Args:  cobra.ExactArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client := createClient()
-			_, err := client.Login()
+			_, err := ensureLogin(client)
 			if err != nil {
 				return fmt.Errorf("登录失败: %w", err)
 			}
@@ -135,12 +135,13 @@ func init() {
 	}
 
 	var deleteNamespaceCmd = &cobra.Command{
-		Use:   "delete [namespace-id]",
-		Short: "删除命名空间",
-		Args:  cobra.ExactArgs(1),
+		Use:               "delete [namespace-id]",
+		Short:             "删除命名空间",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeNamespaces,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client := createClient()
-			_, err := client.Login()
+			_, err := ensureLogin(client)
 			if err != nil {
 				return fmt.Errorf("登录失败: %w", err)
 			}
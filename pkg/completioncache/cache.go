@@ -0,0 +1,123 @@
+// Package completioncache 缓存 shell 补全用的配置和命名空间列表，避免每次按 Tab 都请求服务端
+package completioncache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 超过该时长的条目在写入时清理
+const retention = 7 * 24 * time.Hour
+
+type entry struct {
+	Values  []string `json:"values"`
+	Updated int64    `json:"updated"`          // Unix时间戳
+	Failed  int64    `json:"failed,omitempty"` // 最近一次刷新失败的Unix时间戳
+}
+
+// 条目最近一次更新或刷新失败的时间
+func (e entry) touched() time.Time {
+	if e.Failed > e.Updated {
+		return time.Unix(e.Failed, 0)
+	}
+	return time.Unix(e.Updated, 0)
+}
+
+// Cache 按键保存字符串列表的文件缓存。写入时先写临时文件再重命名，
+// 多个进程同时写入时以最后一次为准
+type Cache struct {
+	Path string
+	TTL  time.Duration // 条目的有效期，超过后仍可读取，但 Get 返回 fresh 为 false
+}
+
+// New 创建补全缓存
+func New(path string, ttl time.Duration) *Cache {
+	return &Cache{Path: path, TTL: ttl}
+}
+
+// Get 返回缓存的列表。ok 表示存在该条目，fresh 表示未超过有效期，或有效期内刷新失败过，此时无需再次刷新；
+// 过期的条目仍会返回，便于无法连接服务端时使用
+func (c *Cache) Get(key string) (values []string, fresh bool, ok bool) {
+	entries := c.load()
+	e, ok := entries[key]
+	if !ok {
+		return nil, false, false
+	}
+	return e.Values, time.Since(e.touched()) < c.TTL, true
+}
+
+// Put 保存列表，同时清理长期未更新的条目
+func (c *Cache) Put(key string, values []string) error {
+	return c.update(key, func(e *entry) {
+		*e = entry{Values: values, Updated: time.Now().Unix()}
+	})
+}
+
+// Fail 记录刷新失败，保留原有的列表。有效期内 Get 不再要求刷新，
+// 服务端不可用时后续的补全直接使用过期的列表，而不是每次都等待超时
+func (c *Cache) Fail(key string) error {
+	return c.update(key, func(e *entry) {
+		e.Failed = time.Now().Unix()
+	})
+}
+
+func (c *Cache) update(key string, modify func(e *entry)) error {
+	entries := c.load()
+	for k, e := range entries {
+		if time.Since(e.touched()) > retention {
+			delete(entries, k)
+		}
+	}
+	e := entries[key]
+	modify(&e)
+	entries[key] = e
+	return c.save(entries)
+}
+
+// 读取缓存，文件不存在或损坏时当作空缓存处理
+func (c *Cache) load() map[string]entry {
+	entries := make(map[string]entry)
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		return entries
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return make(map[string]entry)
+	}
+	return entries
+}
+
+func (c *Cache) save(entries map[string]entry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.Path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("创建补全缓存目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".completion-*")
+	if err != nil {
+		return fmt.Errorf("写入补全缓存失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入补全缓存失败: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入补全缓存失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入补全缓存失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.Path); err != nil {
+		return fmt.Errorf("写入补全缓存失败: %w", err)
+	}
+	return nil
+}
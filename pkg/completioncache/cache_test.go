@@ -0,0 +1,123 @@
+package completioncache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 直接写入缓存文件，用于构造指定时间更新的条目
+func writeEntries(t *testing.T, c *Cache, entries map[string]entry) {
+	t.Helper()
+	data, err := json.Marshal(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(c.Path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func ago(d time.Duration) int64 {
+	return time.Now().Add(-d).Unix()
+}
+
+func TestCacheFreshness(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "nacos", "completion.json"), time.Minute)
+	if values, fresh, ok := c.Get("k"); ok || fresh || values != nil {
+		t.Fatalf("Get() on missing file = %v, %v, %v, want nothing", values, fresh, ok)
+	}
+
+	if err := c.Put("k", []string{"a", "b"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	values, fresh, ok := c.Get("k")
+	if !ok || !fresh || !reflect.DeepEqual(values, []string{"a", "b"}) {
+		t.Errorf("Get() after Put() = %v, %v, %v, want fresh [a b]", values, fresh, ok)
+	}
+
+	// 超过有效期的条目仍然返回，便于无法连接服务端时使用
+	writeEntries(t, c, map[string]entry{"k": {Values: []string{"old"}, Updated: ago(2 * time.Minute)}})
+	values, fresh, ok = c.Get("k")
+	if !ok || fresh || !reflect.DeepEqual(values, []string{"old"}) {
+		t.Errorf("Get() on stale entry = %v, %v, %v, want stale [old]", values, fresh, ok)
+	}
+}
+
+func TestCacheFail(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "completion.json"), time.Minute)
+	writeEntries(t, c, map[string]entry{"k": {Values: []string{"old"}, Updated: ago(time.Hour)}})
+
+	// 刷新失败后在有效期内不再要求刷新，并保留原有的列表
+	if err := c.Fail("k"); err != nil {
+		t.Fatalf("Fail() error: %v", err)
+	}
+	values, fresh, ok := c.Get("k")
+	if !ok || !fresh || !reflect.DeepEqual(values, []string{"old"}) {
+		t.Errorf("Get() after Fail() = %v, %v, %v, want [old] without refresh", values, fresh, ok)
+	}
+
+	writeEntries(t, c, map[string]entry{"k": {Values: []string{"old"}, Updated: ago(time.Hour), Failed: ago(2 * time.Minute)}})
+	if _, fresh, _ := c.Get("k"); fresh {
+		t.Error("Get() = fresh after the failure expired, want refresh")
+	}
+
+	// 从未成功获取过的列表也记录失败
+	if err := c.Fail("new"); err != nil {
+		t.Fatalf("Fail() error: %v", err)
+	}
+	if values, fresh, ok := c.Get("new"); !ok || !fresh || values != nil {
+		t.Errorf("Get() after Fail() on missing entry = %v, %v, %v, want empty without refresh", values, fresh, ok)
+	}
+
+	// 成功刷新后清除失败记录
+	if err := c.Put("k", []string{"new"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if e := c.load()["k"]; e.Failed != 0 || !reflect.DeepEqual(e.Values, []string{"new"}) {
+		t.Errorf("entry after Put() = %+v, want new values without failure", e)
+	}
+}
+
+func TestCacheRetention(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "completion.json"), time.Minute)
+	writeEntries(t, c, map[string]entry{
+		"old":           {Values: []string{"x"}, Updated: ago(retention + time.Hour)},
+		"recent":        {Values: []string{"y"}, Updated: ago(time.Hour)},
+		"recent-failed": {Values: []string{"z"}, Updated: ago(retention + time.Hour), Failed: ago(time.Hour)},
+	})
+
+	if err := c.Put("k", []string{"a"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	entries := c.load()
+	if _, ok := entries["old"]; ok {
+		t.Error("Put() kept an entry older than the retention period")
+	}
+	for _, key := range []string{"recent", "recent-failed", "k"} {
+		if _, ok := entries[key]; !ok {
+			t.Errorf("Put() removed entry %s", key)
+		}
+	}
+}
+
+func TestCacheCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "completion.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := New(path, time.Minute)
+	if _, _, ok := c.Get("k"); ok {
+		t.Error("Get() on corrupt file found an entry")
+	}
+	// 损坏的缓存在下次写入时被覆盖
+	if err := c.Put("k", []string{"a"}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if values, fresh, ok := c.Get("k"); !ok || !fresh || !reflect.DeepEqual(values, []string{"a"}) {
+		t.Errorf("Get() after overwriting corrupt file = %v, %v, %v, want fresh [a]", values, fresh, ok)
+	}
+}
@@ -0,0 +1,211 @@
+package configtree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConvertOptions 转换配置格式时的选项
+type ConvertOptions struct {
+	// ASCII 转为 properties 时将非 ASCII 字符写作 \uXXXX，用于按 ISO-8859-1 读取 properties 的程序
+	ASCII bool
+}
+
+// Convert 在 yaml、json、properties 格式之间转换配置，保留键的顺序。
+// properties 的键按点和下标展开为嵌套的对象和数组，转为 properties 时嵌套的键以点连接，数组下标写作 [n]。
+// yaml 的锚点、别名和合并键会被展开，注释不会保留
+func Convert(from, to string, content []byte, options ConvertOptions) ([]byte, error) {
+	if !Supported(from) {
+		return nil, fmt.Errorf("不支持转换 %s 类型的配置", from)
+	}
+	if !Supported(to) {
+		return nil, fmt.Errorf("不支持转换为 %s 类型", to)
+	}
+	if from == to {
+		return nil, fmt.Errorf("配置已经是 %s 类型", to)
+	}
+
+	var root *yaml.Node
+	if from == "properties" {
+		properties, err := ParseProperties(string(content))
+		if err != nil {
+			return nil, err
+		}
+		if root, err = ExpandProperties(properties); err != nil {
+			return nil, err
+		}
+	} else {
+		doc, err := parseNode(from, content)
+		if err != nil {
+			return nil, fmt.Errorf("解析 %s 配置失败: %w", from, err)
+		}
+		if root, err = plainNode(doc.Content[0], 0); err != nil {
+			return nil, err
+		}
+	}
+
+	switch to {
+	case "properties":
+		var b strings.Builder
+		if err := writeProperties(&b, "", root, options); err != nil {
+			return nil, err
+		}
+		return []byte(b.String()), nil
+	case "json":
+		return encodeJSONNode(root, "  ")
+	default:
+		if root.Kind == yaml.MappingNode && len(root.Content) == 0 {
+			return nil, nil
+		}
+		quoteYAML11(root)
+		return encodeNode("yaml", root, "  ")
+	}
+}
+
+// 复制节点，展开别名和合并键并去掉注释、锚点和样式
+func plainNode(node *yaml.Node, depth int) (*yaml.Node, error) {
+	if depth > 100 {
+		return nil, fmt.Errorf("yaml 嵌套过深或别名存在循环引用")
+	}
+	if node.Kind == yaml.AliasNode {
+		return plainNode(node.Alias, depth+1)
+	}
+
+	plain := &yaml.Node{Kind: node.Kind, Tag: node.ShortTag(), Value: node.Value}
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			item, err := plainNode(child, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			plain.Content = append(plain.Content, item)
+		}
+	case yaml.MappingNode:
+		var merged []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.ShortTag() == "!!merge" {
+				sources := []*yaml.Node{value}
+				if value.Kind == yaml.SequenceNode {
+					sources = value.Content
+				}
+				for _, source := range sources {
+					source, err := plainNode(source, depth+1)
+					if err != nil {
+						return nil, err
+					}
+					if source.Kind != yaml.MappingNode {
+						return nil, fmt.Errorf("第%d行的合并键 << 的值不是对象", key.Line)
+					}
+					merged = append(merged, source)
+				}
+				continue
+			}
+			child, err := plainNode(value, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if j := mappingIndex(plain, key.Value); j >= 0 {
+				plain.Content[j+1] = child
+				continue
+			}
+			plain.Content = append(plain.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.Value}, child)
+		}
+		// 合并进来的键不覆盖已有的键，多个来源时先出现的优先
+		for _, source := range merged {
+			for i := 0; i+1 < len(source.Content); i += 2 {
+				if mappingIndex(plain, source.Content[i].Value) < 0 {
+					plain.Content = append(plain.Content, source.Content[i], source.Content[i+1])
+				}
+			}
+		}
+	}
+	return plain, nil
+}
+
+// YAML 1.1 中表示布尔值的写法。yaml.v3 按 YAML 1.2 输出时不会给这些字符串加引号，
+// 而 Spring 使用的 SnakeYAML 按 YAML 1.1 解析，会把 yes、on 等读成布尔值
+var yaml11Keywords = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true, "n": true, "N": true, "no": true, "No": true, "NO": true,
+	"on": true, "On": true, "ON": true, "off": true, "Off": true, "OFF": true,
+}
+
+// 给 YAML 1.1 会解析为其他类型的字符串加上引号
+func quoteYAML11(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && yaml11Keywords[node.Value] {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		quoteYAML11(child)
+	}
+}
+
+// 将节点展开为 properties 的各行。null 和空的对象、数组写作空值
+func writeProperties(b *strings.Builder, prefix string, node *yaml.Node, options ConvertOptions) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		if len(node.Content) == 0 && prefix != "" {
+			writeProperty(b, prefix, "", options)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := writeProperties(b, joinKey(prefix, node.Content[i].Value), node.Content[i+1], options); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		if prefix == "" {
+			return fmt.Errorf("顶层为数组的配置无法转为 properties")
+		}
+		if len(node.Content) == 0 {
+			writeProperty(b, prefix, "", options)
+		}
+		for i, child := range node.Content {
+			if err := writeProperties(b, prefix+"["+strconv.Itoa(i)+"]", child, options); err != nil {
+				return err
+			}
+		}
+	default:
+		if prefix == "" {
+			return fmt.Errorf("顶层为标量的配置无法转为 properties")
+		}
+		value := node.Value
+		if node.ShortTag() == "!!null" {
+			value = ""
+		}
+		writeProperty(b, prefix, value, options)
+	}
+	return nil
+}
+
+func writeProperty(b *strings.Builder, key, value string, options ConvertOptions) {
+	key, value = EscapePropertyKey(key), EscapePropertyValue(value)
+	if options.ASCII {
+		key, value = escapeUnicode(key), escapeUnicode(value)
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// 将非 ASCII 字符写作 \uXXXX，超出基本平面的字符写作代理对
+func escapeUnicode(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < 0x80 {
+			b.WriteRune(r)
+			continue
+		}
+		if r > 0xFFFF {
+			r -= 0x10000
+			fmt.Fprintf(&b, `\u%04X\u%04X`, 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+			continue
+		}
+		fmt.Fprintf(&b, `\u%04X`, r)
+	}
+	return b.String()
+}
@@ -0,0 +1,137 @@
+package configtree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		ascii    bool
+		content  string
+		want     string
+	}{
+		{
+			name: "properties index expansion", from: "properties", to: "yaml",
+			content: "servers[0].host=a\nservers[0].port=8080\nservers[1].host=b\nmatrix[0][0]=1\n",
+			want:    "servers:\n  - host: a\n    port: 8080\n  - host: b\nmatrix:\n  - - 1\n",
+		},
+		{
+			name: "yaml index flattening", from: "yaml", to: "properties",
+			content: "servers:\n  - host: a\n    port: 8080\n  - b\nempty: {}\nlist: []\nnothing: null\n",
+			want:    "servers[0].host=a\nservers[0].port=8080\nservers[1]=b\nempty=\nlist=\nnothing=\n",
+		},
+		{
+			name: "properties keeps key order", from: "properties", to: "json",
+			content: "z=1\na.y=true\na.b=x\n",
+			want:    "{\n  \"z\": 1,\n  \"a\": {\n    \"y\": true,\n    \"b\": \"x\"\n  }\n}\n",
+		},
+		{
+			name: "properties unicode escapes decoded", from: "properties", to: "json",
+			content: "name=\\u8ba2\\u5355\nemoji=\\uD83D\\uDE00\n",
+			want:    "{\n  \"name\": \"订单\",\n  \"emoji\": \"😀\"\n}\n",
+		},
+		{
+			name: "ascii escapes non-ASCII", from: "yaml", to: "properties", ascii: true,
+			content: "name: 订单\nemoji: 😀\n键: v\n",
+			want:    "name=\\u8BA2\\u5355\nemoji=\\uD83D\\uDE00\n\\u952E=v\n",
+		},
+		{
+			name: "utf-8 without ascii", from: "yaml", to: "properties",
+			content: "name: 订单\n",
+			want:    "name=订单\n",
+		},
+		{
+			name: "property values escaped", from: "json", to: "properties",
+			content: `{"k": " lead", "multi": "a\nb", "url": "http://x:1/?a=b"}`,
+			want:    "k=\\ lead\nmulti=a\\nb\nurl=http://x:1/?a=b\n",
+		},
+		{
+			name: "yaml 1.1 keywords quoted", from: "properties", to: "yaml",
+			content: "a=yes\nb=on\nc=No\nd=OFF\ne=y\nf=true\ng=1\nh=x\n",
+			want:    "a: \"yes\"\nb: \"on\"\nc: \"No\"\nd: \"OFF\"\ne: \"y\"\nf: true\ng: 1\nh: x\n",
+		},
+		{
+			name: "yaml 1.1 keywords from json", from: "json", to: "yaml",
+			content: `{"a": "yes", "b": "off", "c": true, "list": ["n", "no"]}`,
+			want:    "a: \"yes\"\nb: \"off\"\nc: true\nlist:\n  - \"n\"\n  - \"no\"\n",
+		},
+		{
+			name: "merge keys and aliases", from: "yaml", to: "json",
+			content: "base: &base\n  x: 1\n  y: 2\nchild:\n  <<: *base\n  y: 3\nref: *base\n",
+			want:    "{\n  \"base\": {\n    \"x\": 1,\n    \"y\": 2\n  },\n  \"child\": {\n    \"y\": 3,\n    \"x\": 1\n  },\n  \"ref\": {\n    \"x\": 1,\n    \"y\": 2\n  }\n}\n",
+		},
+		{
+			name: "merge key list, first source wins", from: "yaml", to: "properties",
+			content: "a: &a\n  x: 1\nb: &b\n  x: 2\n  z: 3\nc:\n  <<: [*a, *b]\n",
+			want:    "a.x=1\nb.x=2\nb.z=3\nc.x=1\nc.z=3\n",
+		},
+		{
+			name: "comments dropped", from: "yaml", to: "json",
+			content: "# comment\na: 1 # trailing\n",
+			want:    "{\n  \"a\": 1\n}\n",
+		},
+		{
+			name: "empty properties", from: "properties", to: "yaml",
+			content: "# only a comment\n",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.from, tt.to, []byte(tt.content), ConvertOptions{ASCII: tt.ascii})
+			if err != nil {
+				t.Fatalf("Convert() error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Convert() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	content := "spring:\n  application:\n    name: 订单服务\n  profiles:\n    active:\n      - dev\n      - \"on\"\n"
+	properties, err := Convert("yaml", "properties", []byte(content), ConvertOptions{ASCII: true})
+	if err != nil {
+		t.Fatalf("Convert(yaml, properties) error: %v", err)
+	}
+	back, err := Convert("properties", "yaml", properties, ConvertOptions{})
+	if err != nil {
+		t.Fatalf("Convert(properties, yaml) error: %v", err)
+	}
+	if string(back) != content {
+		t.Errorf("round trip through %q = %q, want %q", properties, back, content)
+	}
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name, from, to, content, want string
+	}{
+		{"index not starting at zero", "properties", "json", "list[1]=x\n", "下标不连续"},
+		{"index gap", "properties", "yaml", "list[0]=x\nlist[2]=y\n", "下标不连续"},
+		{"value then object", "properties", "json", "a=1\na.b=2\n", "既是值又是对象或数组"},
+		{"object then value", "properties", "json", "a.b=2\na=1\n", "已经是对象或数组"},
+		{"invalid unicode escape", "properties", "yaml", "a=\\u12\n", "无效的 Unicode 转义"},
+		{"top-level array", "yaml", "properties", "- a\n", "顶层为数组"},
+		{"top-level scalar", "json", "properties", `"a"`, "顶层为标量"},
+		{"merge key not an object", "yaml", "json", "a:\n  <<: 1\n", "合并键 << 的值不是对象"},
+		{"invalid yaml", "yaml", "json", "a: [\n", "解析 yaml 配置失败"},
+		{"same type", "yaml", "yaml", "a: 1\n", "已经是 yaml 类型"},
+		{"unsupported source", "xml", "yaml", "<a/>", "不支持转换 xml"},
+		{"unsupported target", "yaml", "toml", "a: 1\n", "不支持转换为 toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.from, tt.to, []byte(tt.content), ConvertOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Convert() = %q, %v, want error containing %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package configtree
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseNested 与 Parse 相同，但 properties 配置按键名中的点和下标展开为嵌套的对象和数组，
+// 值为整数、小数或布尔值时转为对应的类型。同时存在 a=1 和 a.b=2 时（Spring 配置中常见）保留 a 的值，
+// 忽略 a 下的键，由 schema 检查报告类型不符，而不是解析失败
+func ParseNested(configType string, content []byte) (interface{}, error) {
+	if configType != "properties" {
+		return Parse(configType, content)
+	}
+	properties, err := ParseProperties(string(content))
+	if err != nil {
+		return nil, err
+	}
+	node, err := expandProperties(properties, true)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// ExpandProperties 将 properties 的各项展开为嵌套的 yaml 节点，键的顺序与 properties 中第一次出现的顺序一致。
+// 键名如 servers[0].host 中的下标展开为数组，下标必须从0开始连续出现
+func ExpandProperties(properties []Property) (*yaml.Node, error) {
+	return expandProperties(properties, false)
+}
+
+// 展开 properties。keepScalars 为 true 时，键既是值又是对象或数组的上级时保留值，忽略其下的键
+func expandProperties(properties []Property, keepScalars bool) (*yaml.Node, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, p := range properties {
+		segments, err := ParsePath(p.Key)
+		if err != nil {
+			// 无法按路径解析的键原样作为一个键
+			segments = []Segment{{Key: p.Key}}
+		}
+		if err := insert(root, segments, typedScalar(p.Value), keepScalars); err != nil {
+			return nil, fmt.Errorf("展开键 %s 失败: %w", p.Key, err)
+		}
+	}
+	return root, nil
+}
+
+func insert(node *yaml.Node, segments []Segment, value *yaml.Node, keepScalars bool) error {
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		var next *yaml.Node
+		switch {
+		case node.Kind == yaml.MappingNode && !segment.IsIndex:
+			if j := mappingIndex(node, segment.Key); j >= 0 {
+				next = node.Content[j+1]
+				if last && keepScalars {
+					// 值出现在其下的键之后，用值替换已展开的对象或数组
+					node.Content[j+1] = value
+					return nil
+				}
+				if last {
+					return fmt.Errorf("%s 已经是对象或数组", FormatPath(segments[:i+1]))
+				}
+			} else {
+				next = newChild(segments, i, value)
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment.Key}, next)
+			}
+		case node.Kind == yaml.SequenceNode && segment.IsIndex:
+			switch {
+			case segment.Index < len(node.Content):
+				next = node.Content[segment.Index]
+				if last && keepScalars {
+					node.Content[segment.Index] = value
+					return nil
+				}
+				if last {
+					return fmt.Errorf("%s 已经是对象或数组", FormatPath(segments[:i+1]))
+				}
+			case segment.Index == len(node.Content):
+				next = newChild(segments, i, value)
+				node.Content = append(node.Content, next)
+			default:
+				return fmt.Errorf("%s 的下标不连续", FormatPath(segments[:i+1]))
+			}
+		default:
+			return fmt.Errorf("%s 既是值又是对象或数组", displayPath(segments[:i]))
+		}
+		if next.Kind == yaml.ScalarNode && !last {
+			if keepScalars {
+				return nil
+			}
+			return fmt.Errorf("%s 既是值又是对象或数组", FormatPath(segments[:i+1]))
+		}
+		node = next
+	}
+	return nil
+}
+
+// 将 properties 中的文本值转为带类型的标量节点。只转换写法规范、转回文本后不变的数字，
+// 避免 007、1.50 等值丢失信息
+func typedScalar(value string) *yaml.Node {
+	tag := "!!str"
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(n, 10) == value {
+		tag = "!!int"
+	} else if f, err := strconv.ParseFloat(value, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == value {
+		tag = "!!float"
+	} else if value == "true" || value == "false" {
+		tag = "!!bool"
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
@@ -0,0 +1,66 @@
+package configtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNestedProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    interface{}
+	}{
+		{
+			name:    "nested keys and typed values",
+			content: "server.port=8080\nserver.ssl=true\nratio=0.5\nzip=007\n",
+			want: map[string]interface{}{
+				"server": map[string]interface{}{"port": 8080, "ssl": true},
+				"ratio":  0.5,
+				"zip":    "007",
+			},
+		},
+		{
+			name:    "arrays",
+			content: "servers[0].host=a\nservers[1].host=b\n",
+			want: map[string]interface{}{
+				"servers": []interface{}{map[string]interface{}{"host": "a"}, map[string]interface{}{"host": "b"}},
+			},
+		},
+		{
+			name:    "value before nested key",
+			content: "a=1\na.b=2\n",
+			want:    map[string]interface{}{"a": 1},
+		},
+		{
+			name:    "value after nested key",
+			content: "a.b=2\na=1\nlist[0].x=1\nlist[0]=y\n",
+			want:    map[string]interface{}{"a": 1, "list": []interface{}{"y"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNested("properties", []byte(tt.content))
+			if err != nil {
+				t.Fatalf("ParseNested() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseNested() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandPropertiesConflicts(t *testing.T) {
+	// 转换格式时无法同时保留值和其下的键，仍然报错
+	for _, content := range []string{"a=1\na.b=2\n", "a.b=2\na=1\n", "list[1]=x\n"} {
+		properties, err := ParseProperties(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ExpandProperties(properties); err == nil {
+			t.Errorf("ExpandProperties(%q) succeeded, want error", content)
+		}
+	}
+}
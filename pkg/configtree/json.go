@@ -0,0 +1,187 @@
+package configtree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 将 json 解析为 yaml 节点，保留对象中键的顺序。空内容返回 nil
+func parseJSONNode(content []byte) (*yaml.Node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	node, err := readJSONValue(decoder)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("json 值之后有多余的内容")
+	}
+	return node, nil
+}
+
+func readJSONValue(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '{' {
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := readJSONValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: keyToken.(string)}
+				node.Content = append(node.Content, key, value)
+			}
+			_, err := decoder.Token()
+			return node, err
+		}
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for decoder.More() {
+			value, err := readJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		_, err := decoder.Token()
+		return node, err
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(t)}, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+}
+
+// 将节点编码为 json。indent 为空时输出紧凑格式
+func encodeJSONNode(node *yaml.Node, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, node, indent, 0); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+func writeJSON(buf *bytes.Buffer, node *yaml.Node, indent string, level int) error {
+	newline := func(level int) {
+		if indent != "" {
+			buf.WriteByte('\n')
+			buf.WriteString(strings.Repeat(indent, level))
+		}
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, node.Content[0], indent, level)
+	case yaml.AliasNode:
+		return writeJSON(buf, node.Alias, indent, level)
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(level + 1)
+			writeJSONString(buf, node.Content[i].Value)
+			buf.WriteByte(':')
+			if indent != "" {
+				buf.WriteByte(' ')
+			}
+			if err := writeJSON(buf, node.Content[i+1], indent, level+1); err != nil {
+				return err
+			}
+		}
+		newline(level)
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i, child := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			newline(level + 1)
+			if err := writeJSON(buf, child, indent, level+1); err != nil {
+				return err
+			}
+		}
+		newline(level)
+		buf.WriteByte(']')
+	default:
+		return writeJSONScalar(buf, node)
+	}
+	return nil
+}
+
+func writeJSONScalar(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!int", "!!float", "!!bool":
+		if node.Value == "true" || node.Value == "false" || jsonNumber.MatchString(node.Value) {
+			buf.WriteString(node.Value)
+			return nil
+		}
+		// yaml 的其他写法，如 0x1F、True，按解码后的值输出
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("无法将 %s 转为 json: %w", node.Value, err)
+		}
+		buf.Write(data)
+		return nil
+	default:
+		writeJSONString(buf, node.Value)
+		return nil
+	}
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	// Encode 会追加换行符
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,442 @@
+package configtree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrKeyNotFound 键路径在配置中不存在
+var ErrKeyNotFound = errors.New("键不存在")
+
+// GetKey 读取键路径的值。标量返回其文本，对象和数组按配置本身的格式输出。
+// properties 配置的键不分段，path 即完整的键名
+func GetKey(configType string, content []byte, path string) (string, error) {
+	if configType == "properties" {
+		properties, err := ParseProperties(string(content))
+		if err != nil {
+			return "", err
+		}
+		for _, p := range properties {
+			if p.Key == path {
+				return p.Value, nil
+			}
+		}
+		return "", fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+	}
+
+	segments, err := ParsePath(path)
+	if err != nil {
+		return "", err
+	}
+	doc, err := parseNode(configType, content)
+	if err != nil {
+		return "", err
+	}
+	node, err := find(doc.Content[0], segments)
+	if err != nil {
+		return "", err
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value, nil
+	}
+	data, err := encodeNode(configType, node, "  ")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// SetKey 设置键路径的值，不存在的键和上级对象会被创建，数组下标等于数组长度时追加元素。
+// 值按 yaml（json 配置按 json）解析，无法解析时作为字符串。
+// yaml 配置的单行标量直接在原文中替换，其他修改通过 yaml.v3 节点完成，注释和键的顺序保持不变
+func SetKey(configType string, content []byte, path, value string) ([]byte, error) {
+	if configType == "properties" {
+		result, err := setProperty(string(content), path, value)
+		return []byte(result), err
+	}
+
+	segments, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseNode(configType, content)
+	if err != nil {
+		return nil, err
+	}
+	newNode := parseValue(configType, value)
+
+	if configType == "yaml" {
+		if old, err := find(doc.Content[0], segments); err == nil {
+			if result, ok := spliceScalar(content, segments, old, newNode); ok {
+				return result, nil
+			}
+		}
+	}
+
+	if err := setNode(doc.Content[0], segments, newNode); err != nil {
+		return nil, err
+	}
+	return encodeLike(configType, doc, content)
+}
+
+// UnsetKey 删除键路径对应的键或数组元素
+func UnsetKey(configType string, content []byte, path string) ([]byte, error) {
+	if configType == "properties" {
+		result, err := unsetProperty(string(content), path)
+		return []byte(result), err
+	}
+
+	segments, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseNode(configType, content)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := find(doc.Content[0], segments[:len(segments)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := segments[len(segments)-1]
+	switch {
+	case parent.Kind == yaml.MappingNode && !last.IsIndex:
+		i := mappingIndex(parent, last.Key)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+		}
+		parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+	case parent.Kind == yaml.SequenceNode && last.IsIndex:
+		if last.Index >= len(parent.Content) {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+		}
+		parent.Content = append(parent.Content[:last.Index], parent.Content[last.Index+1:]...)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, path)
+	}
+	return encodeLike(configType, doc, content)
+}
+
+// 解析为 yaml 文档节点，文档的根节点为 Content[0]。空配置的根节点为空的对象
+func parseNode(configType string, content []byte) (*yaml.Node, error) {
+	empty := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	switch configType {
+	case "yaml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return empty, nil
+			}
+			return nil, err
+		}
+		var next yaml.Node
+		if err := decoder.Decode(&next); err != io.EOF {
+			return nil, fmt.Errorf("配置包含多个 yaml 文档，不支持按键读取和修改")
+		}
+		if len(doc.Content) == 0 {
+			return empty, nil
+		}
+		return &doc, nil
+	case "json":
+		root, err := parseJSONNode(content)
+		if err != nil {
+			return nil, err
+		}
+		if root == nil {
+			return empty, nil
+		}
+		return &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}, nil
+	default:
+		return nil, fmt.Errorf("不支持按键读取和修改 %s 类型的配置", configType)
+	}
+}
+
+// 将命令行上的值解析为节点
+func parseValue(configType string, value string) *yaml.Node {
+	if configType == "json" {
+		if node, err := parseJSONNode([]byte(value)); err == nil {
+			return node
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	}
+
+	var doc yaml.Node
+	if value == "" || yaml.Unmarshal([]byte(value), &doc) != nil || len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	}
+	node := doc.Content[0]
+	clearPosition(node)
+	return node
+}
+
+// 清除节点的位置信息，避免编码时沿用命令行上的格式
+func clearPosition(node *yaml.Node) {
+	node.Line, node.Column = 0, 0
+	for _, child := range node.Content {
+		clearPosition(child)
+	}
+}
+
+func find(root *yaml.Node, segments []Segment) (*yaml.Node, error) {
+	node := root
+	for i, segment := range segments {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		var next *yaml.Node
+		switch {
+		case node.Kind == yaml.MappingNode && !segment.IsIndex:
+			if j := mappingIndex(node, segment.Key); j >= 0 {
+				next = node.Content[j+1]
+			}
+		case node.Kind == yaml.SequenceNode && segment.IsIndex:
+			if segment.Index < len(node.Content) {
+				next = node.Content[segment.Index]
+			}
+		}
+		if next == nil {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, FormatPath(segments[:i+1]))
+		}
+		node = next
+	}
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node, nil
+}
+
+// 对象中键的位置，即键节点在 Content 中的下标，不存在时返回 -1
+func mappingIndex(node *yaml.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func setNode(root *yaml.Node, segments []Segment, value *yaml.Node) error {
+	node := root
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		if node.Kind == yaml.AliasNode {
+			return fmt.Errorf("%s 是别名，不支持修改", FormatPath(segments[:i]))
+		}
+		// 空值可以直接变为对象或数组
+		if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+			if segment.IsIndex {
+				node.Kind, node.Tag = yaml.SequenceNode, "!!seq"
+			} else {
+				node.Kind, node.Tag = yaml.MappingNode, "!!map"
+			}
+			node.Value, node.Style = "", 0
+		}
+
+		var child *yaml.Node
+		switch {
+		case node.Kind == yaml.MappingNode && !segment.IsIndex:
+			if j := mappingIndex(node, segment.Key); j >= 0 {
+				child = node.Content[j+1]
+			} else {
+				child = newChild(segments, i, value)
+				key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: segment.Key}
+				node.Content = append(node.Content, key, child)
+				if last {
+					return nil
+				}
+			}
+		case node.Kind == yaml.SequenceNode && segment.IsIndex:
+			switch {
+			case segment.Index < len(node.Content):
+				child = node.Content[segment.Index]
+			case segment.Index == len(node.Content):
+				child = newChild(segments, i, value)
+				node.Content = append(node.Content, child)
+				if last {
+					return nil
+				}
+			default:
+				return fmt.Errorf("%s 的下标超出范围，数组长度为 %d", FormatPath(segments[:i+1]), len(node.Content))
+			}
+		case segment.IsIndex:
+			return fmt.Errorf("%s 不是数组", displayPath(segments[:i]))
+		default:
+			return fmt.Errorf("%s 不是对象", displayPath(segments[:i]))
+		}
+
+		if last {
+			replaceNode(child, value)
+			return nil
+		}
+		node = child
+	}
+	return nil
+}
+
+// 为第 i 段路径新建的节点：最后一段为要设置的值，否则按下一段新建对象或数组
+func newChild(segments []Segment, i int, value *yaml.Node) *yaml.Node {
+	if i == len(segments)-1 {
+		return value
+	}
+	if segments[i+1].IsIndex {
+		return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+}
+
+// 用新的值替换节点，保留原节点的注释，字符串沿用原有的引号风格
+func replaceNode(old, value *yaml.Node) {
+	replacement := *value
+	replacement.HeadComment = old.HeadComment
+	replacement.LineComment = old.LineComment
+	replacement.FootComment = old.FootComment
+	if replacement.Kind == yaml.ScalarNode && old.Kind == yaml.ScalarNode && replacement.Style == 0 && replacement.Tag == "!!str" {
+		replacement.Style = old.Style
+	}
+	*old = replacement
+}
+
+func displayPath(segments []Segment) string {
+	if len(segments) == 0 {
+		return "根节点"
+	}
+	return FormatPath(segments)
+}
+
+// 在原文中直接替换单行标量，保留空行等 yaml.v3 重新编码时会丢失的格式。无法安全替换时返回 false
+func spliceScalar(content []byte, segments []Segment, old, value *yaml.Node) ([]byte, bool) {
+	if old.Kind != yaml.ScalarNode || value.Kind != yaml.ScalarNode || old.Line == 0 || old.Anchor != "" {
+		return nil, false
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if old.Line > len(lines) {
+		return nil, false
+	}
+	line := lines[old.Line-1]
+	runes := []rune(line)
+	if old.Column-1 > len(runes) {
+		return nil, false
+	}
+	start := len(string(runes[:old.Column-1]))
+	length := scalarLength(line[start:], old)
+	if length < 0 {
+		return nil, false
+	}
+
+	replacement := *value
+	if replacement.Style == 0 && replacement.Tag == "!!str" {
+		replacement.Style = old.Style
+	}
+	data, err := yaml.Marshal(&replacement)
+	if err != nil {
+		return nil, false
+	}
+	token := strings.TrimSuffix(string(data), "\n")
+	if strings.Contains(token, "\n") {
+		return nil, false
+	}
+
+	lines[old.Line-1] = line[:start] + token + line[start+length:]
+	result := []byte(strings.Join(lines, ""))
+
+	// 重新解析确认替换的结果正确
+	doc, err := parseNode("yaml", result)
+	if err != nil {
+		return nil, false
+	}
+	if node, err := find(doc.Content[0], segments); err != nil || node.Value != value.Value || node.ShortTag() != value.ShortTag() {
+		return nil, false
+	}
+	return result, true
+}
+
+// 标量在原文中所占的字节数，无法确定时返回 -1
+func scalarLength(text string, node *yaml.Node) int {
+	switch node.Style {
+	case 0:
+		if !strings.HasPrefix(text, node.Value) {
+			return -1
+		}
+		return len(node.Value)
+	case yaml.DoubleQuotedStyle:
+		for i := 1; i < len(text); i++ {
+			switch text[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			case '\n':
+				return -1
+			}
+		}
+	case yaml.SingleQuotedStyle:
+		for i := 1; i < len(text); i++ {
+			if text[i] == '\n' {
+				return -1
+			}
+			if text[i] == '\'' {
+				if i+1 < len(text) && text[i+1] == '\'' {
+					i++
+					continue
+				}
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// 推测 yaml 或 json 配置的缩进，用于重新编码。没有缩进的行时返回空字符串
+func detectIndent(content []byte) string {
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || trimmed[0] == '#' || len(trimmed) == len(line) {
+			continue
+		}
+		return line[:len(line)-len(trimmed)]
+	}
+	return ""
+}
+
+// 按原配置的缩进和末尾换行编码修改后的文档
+func encodeLike(configType string, doc *yaml.Node, content []byte) ([]byte, error) {
+	data, err := encodeNode(configType, doc, detectIndent(content))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		data = bytes.TrimSuffix(data, []byte("\n"))
+	}
+	return data, nil
+}
+
+// 按配置类型编码节点
+func encodeNode(configType string, node *yaml.Node, indent string) ([]byte, error) {
+	if configType == "json" {
+		if node.Kind == yaml.DocumentNode {
+			node = node.Content[0]
+		}
+		return encodeJSONNode(node, indent)
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	width := len(strings.ReplaceAll(indent, "\t", "  "))
+	if width < 2 {
+		width = 2
+	}
+	encoder.SetIndent(width)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
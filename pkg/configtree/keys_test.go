@@ -0,0 +1,183 @@
+package configtree
+
+import (
+	"errors"
+	"testing"
+)
+
+const keysYAML = `# 服务配置
+server:
+  port: 8080 # 端口
+
+  name: "order"
+servers:
+  - host: a
+  - host: b
+`
+
+func TestGetKey(t *testing.T) {
+	tests := []struct {
+		configType, content, path, want string
+	}{
+		{"yaml", keysYAML, "server.port", "8080"},
+		{"yaml", keysYAML, "servers[1].host", "b"},
+		{"yaml", keysYAML, "server", "port: 8080 # 端口\nname: \"order\""},
+		{"json", `{"a": {"b": [1, 2]}}`, "a.b[0]", "1"},
+		{"json", `{"a": {"b": [1, 2]}}`, "a", "{\n  \"b\": [\n    1,\n    2\n  ]\n}"},
+		{"json", `{"a.b": true}`, "['a.b']", "true"},
+		{"properties", "a.b=1\na.b.c=2\n", "a.b", "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.configType+" "+tt.path, func(t *testing.T) {
+			got, err := GetKey(tt.configType, []byte(tt.content), tt.path)
+			if err != nil {
+				t.Fatalf("GetKey(%q) error: %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("GetKey(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	for _, path := range []string{"server.missing", "servers[2]", "server.port.x"} {
+		if _, err := GetKey("yaml", []byte(keysYAML), path); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("GetKey(%q) error = %v, want ErrKeyNotFound", path, err)
+		}
+	}
+}
+
+func TestSetKey(t *testing.T) {
+	tests := []struct {
+		name                string
+		configType, content string
+		path, value, want   string
+	}{
+		{
+			name: "yaml scalar keeps comments and blank lines", configType: "yaml", content: keysYAML,
+			path: "server.port", value: "9090",
+			want: "# 服务配置\nserver:\n  port: 9090 # 端口\n\n  name: \"order\"\nservers:\n  - host: a\n  - host: b\n",
+		},
+		{
+			name: "yaml string keeps quotes", configType: "yaml", content: keysYAML,
+			path: "server.name", value: "pay",
+			want: "# 服务配置\nserver:\n  port: 8080 # 端口\n\n  name: \"pay\"\nservers:\n  - host: a\n  - host: b\n",
+		},
+		{
+			name: "yaml new nested key", configType: "yaml", content: "a: 1\n",
+			path: "b.c", value: "true",
+			want: "a: 1\nb:\n  c: true\n",
+		},
+		{
+			name: "yaml append to array", configType: "yaml", content: "list:\n  - x\n",
+			path: "list[1]", value: "y",
+			want: "list:\n  - x\n  - y\n",
+		},
+		{
+			name: "yaml object value", configType: "yaml", content: "a: 1\n",
+			path: "a", value: "{x: 1}",
+			want: "a: {x: 1}\n",
+		},
+		{
+			name: "yaml null becomes object", configType: "yaml", content: "a:\n",
+			path: "a.b", value: "1",
+			want: "a:\n  b: 1\n",
+		},
+		{
+			name: "yaml empty config", configType: "yaml", content: "",
+			path: "a", value: "1",
+			want: "a: 1\n",
+		},
+		{
+			name: "json keeps indent and order", configType: "json", content: "{\n    \"b\": 1,\n    \"a\": 2\n}",
+			path: "a", value: `"x"`,
+			want: "{\n    \"b\": 1,\n    \"a\": \"x\"\n}",
+		},
+		{
+			name: "json plain text value on one line", configType: "json", content: `{"a": 1}`,
+			path: "c", value: "hello",
+			want: `{"a":1,"c":"hello"}`,
+		},
+		{
+			name: "properties replace", configType: "properties", content: "# c\na=1\nb = 2\n",
+			path: "b", value: "3",
+			want: "# c\na=1\nb = 3\n",
+		},
+		{
+			name: "properties replace continuation", configType: "properties", content: "a=x,\\\n  y\nb=2\n",
+			path: "a", value: "z",
+			want: "a=z\nb=2\n",
+		},
+		{
+			name: "properties append escaped", configType: "properties", content: "a=1",
+			path: "k:1", value: " v",
+			want: "a=1\nk\\:1=\\ v",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetKey(tt.configType, []byte(tt.content), tt.path, tt.value)
+			if err != nil {
+				t.Fatalf("SetKey(%q, %q) error: %v", tt.path, tt.value, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("SetKey(%q, %q) = %q, want %q", tt.path, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetKeyErrors(t *testing.T) {
+	tests := []struct {
+		name, configType, content, path string
+	}{
+		{"index out of range", "yaml", "list: [a]\n", "list[3]"},
+		{"index on object", "yaml", "a: {b: 1}\n", "a[0]"},
+		{"key on scalar", "yaml", "a: 1\n", "a.b"},
+		{"key on array", "json", `{"a": [1]}`, "a.b"},
+		{"multiple documents", "yaml", "a: 1\n---\nb: 2\n", "a"},
+		{"unsupported type", "xml", "<a/>", "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := SetKey(tt.configType, []byte(tt.content), tt.path, "1"); err == nil {
+				t.Errorf("SetKey(%q) = %q, want error", tt.path, got)
+			}
+		})
+	}
+}
+
+func TestUnsetKey(t *testing.T) {
+	tests := []struct {
+		name, configType, content, path, want string
+	}{
+		{"yaml key", "yaml", "a: 1\nb: 2\n", "a", "b: 2\n"},
+		{"yaml array element", "yaml", "list:\n  - x\n  - y\n", "list[0]", "list:\n  - y\n"},
+		{"json nested key", "json", "{\n  \"a\": {\n    \"b\": 1,\n    \"c\": 2\n  }\n}\n", "a.b", "{\n  \"a\": {\n    \"c\": 2\n  }\n}\n"},
+		{"properties all definitions", "properties", "a=1\nb=2\na=3\n", "a", "b=2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnsetKey(tt.configType, []byte(tt.content), tt.path)
+			if err != nil {
+				t.Fatalf("UnsetKey(%q) error: %v", tt.path, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("UnsetKey(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	for _, tt := range []struct{ configType, content, path string }{
+		{"yaml", "a: 1\n", "b"},
+		{"yaml", "list: [x]\n", "list[1]"},
+		{"properties", "a=1\n", "b"},
+	} {
+		if _, err := UnsetKey(tt.configType, []byte(tt.content), tt.path); !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("UnsetKey(%q) error = %v, want ErrKeyNotFound", tt.path, err)
+		}
+	}
+}
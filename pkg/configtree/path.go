@@ -0,0 +1,100 @@
+package configtree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Segment 键路径中的一段：对象的键或数组的下标
+type Segment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+func (s Segment) String() string {
+	if s.IsIndex {
+		return "[" + strconv.Itoa(s.Index) + "]"
+	}
+	return s.Key
+}
+
+// ParsePath 解析键路径，如 server.port、servers[0].host。键中包含点时写作 a\.b 或 ['a.b']
+func ParsePath(path string) ([]Segment, error) {
+	var segments []Segment
+	var key strings.Builder
+	pending := false // key 中有尚未结束的键
+
+	flush := func() {
+		if pending {
+			segments = append(segments, Segment{Key: key.String()})
+			key.Reset()
+			pending = false
+		}
+	}
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '\\' && i+1 < len(path):
+			i++
+			key.WriteByte(path[i])
+			pending = true
+		case c == '.':
+			if !pending && (i == 0 || path[i-1] != ']') {
+				return nil, fmt.Errorf("无效的键路径 %s: 第%d个字符处缺少键", path, i+1)
+			}
+			flush()
+		case c == '[':
+			flush()
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("无效的键路径 %s: 缺少 ]", path)
+			}
+			inner := path[i+1 : i+end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, Segment{Key: inner[1 : len(inner)-1]})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("无效的键路径 %s: 下标 %s 不是非负整数", path, inner)
+				}
+				segments = append(segments, Segment{Index: index, IsIndex: true})
+			}
+			i += end
+		default:
+			key.WriteByte(c)
+			pending = true
+		}
+	}
+	if strings.HasSuffix(path, ".") && !strings.HasSuffix(path, `\.`) {
+		return nil, fmt.Errorf("无效的键路径 %s: 以点结尾", path)
+	}
+	flush()
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("键路径不能为空")
+	}
+	return segments, nil
+}
+
+// FormatPath 将键路径格式化为文本，是 ParsePath 的逆操作
+func FormatPath(segments []Segment) string {
+	var b strings.Builder
+	for i, s := range segments {
+		if s.IsIndex {
+			b.WriteString(s.String())
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		for j := 0; j < len(s.Key); j++ {
+			if c := s.Key[j]; c == '.' || c == '[' || c == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(s.Key[j])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package configtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	key := func(k string) Segment { return Segment{Key: k} }
+	index := func(i int) Segment { return Segment{Index: i, IsIndex: true} }
+
+	tests := []struct {
+		path string
+		want []Segment
+	}{
+		{"server", []Segment{key("server")}},
+		{"server.port", []Segment{key("server"), key("port")}},
+		{"servers[0].host", []Segment{key("servers"), index(0), key("host")}},
+		{"matrix[1][2]", []Segment{key("matrix"), index(1), index(2)}},
+		{"[0].name", []Segment{index(0), key("name")}},
+		{`logging\.level.root`, []Segment{key("logging.level"), key("root")}},
+		{"logging['level.root']", []Segment{key("logging"), key("level.root")}},
+		{`logging["level.root"].value`, []Segment{key("logging"), key("level.root"), key("value")}},
+		{`a\\b`, []Segment{key(`a\b`)}},
+		{"中文.键", []Segment{key("中文"), key("键")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParsePath(tt.path)
+			if err != nil {
+				t.Fatalf("ParsePath(%q) error: %v", tt.path, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, path := range []string{"", ".a", "a..b", "a.", "a[", "a[x]", "a[-1]"} {
+		t.Run(path, func(t *testing.T) {
+			if got, err := ParsePath(path); err == nil {
+				t.Errorf("ParsePath(%q) = %v, want error", path, got)
+			}
+		})
+	}
+}
+
+func TestFormatPath(t *testing.T) {
+	for _, path := range []string{"server.port", "servers[0].host", `logging\.level.root`, `a\[0\]`, `a\\b`, "[0][1].x"} {
+		t.Run(path, func(t *testing.T) {
+			segments, err := ParsePath(path)
+			if err != nil {
+				t.Fatalf("ParsePath(%q) error: %v", path, err)
+			}
+			formatted := FormatPath(segments)
+			again, err := ParsePath(formatted)
+			if err != nil {
+				t.Fatalf("ParsePath(%q) error: %v", formatted, err)
+			}
+			if !reflect.DeepEqual(again, segments) {
+				t.Errorf("FormatPath(%v) = %q, which parses to %v", segments, formatted, again)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package configtree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// Property properties 配置中的一项
+type Property struct {
+	Key   string
+	Value string
+}
+
+// properties 配置中的一个逻辑行，可能由多个以反斜杠续行的物理行组成
+type propertyLine struct {
+	Property
+	start, end int    // 起止物理行的下标，不含 end
+	prefix     string // 第一个物理行中值之前的部分，包括缩进、键和分隔符
+}
+
+// ParseProperties 按 Java Properties 的规则解析配置：忽略 # 和 ! 开头的注释行，支持以反斜杠续行，
+// 键和值以第一个未转义的 =、: 或空白分隔，并处理 \uXXXX 等转义。返回的各项保持原有顺序，重复的键以最后一次为准
+func ParseProperties(content string) ([]Property, error) {
+	entries, err := scanProperties(splitLines(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var properties []Property
+	index := make(map[string]int)
+	for _, entry := range entries {
+		if pos, ok := index[entry.Key]; ok {
+			properties[pos].Value = entry.Value
+			continue
+		}
+		index[entry.Key] = len(properties)
+		properties = append(properties, entry.Property)
+	}
+	return properties, nil
+}
+
+func splitLines(content string) []string {
+	return strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+}
+
+// 逐行扫描 properties 配置，返回每个逻辑行的键、值和位置
+func scanProperties(lines []string) ([]propertyLine, error) {
+	var entries []propertyLine
+	for i := 0; i < len(lines); i++ {
+		start := i
+		first := lines[i]
+		line := strings.TrimLeft(first, " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// 以奇数个反斜杠结尾时与下一行相连，下一行开头的空白被忽略
+		for continues(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if continues(line) {
+			line = line[:len(line)-1]
+		}
+
+		rawKey, rawValue := splitProperty(line)
+		key, err := unescapeProperty(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("第%d行: %w", start+1, err)
+		}
+		value, err := unescapeProperty(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("第%d行: %w", start+1, err)
+		}
+
+		entry := propertyLine{Property: Property{Key: key, Value: value}, start: start, end: i + 1}
+		if keyEnd := len(line) - len(rawValue); keyEnd <= len(strings.TrimLeft(first, " \t\f")) {
+			entry.prefix = first[:len(first)-len(strings.TrimLeft(first, " \t\f"))+keyEnd]
+		} else {
+			// 键本身跨越多行，只能按规范格式重写
+			entry.prefix = EscapePropertyKey(key) + "="
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// 行尾是否为未转义的反斜杠
+func continues(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// 拆分键和值，返回的键和值仍包含转义
+func splitProperty(line string) (string, string) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			end = i
+			break
+		}
+	}
+	key := line[:end]
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return key, rest
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("无效的 Unicode 转义: %s", s[i-1:])
+			}
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("无效的 Unicode 转义: %s", s[i-1:i+5])
+			}
+			i += 4
+			r := rune(code)
+			// Java 以 UTF-16 代理对表示基本平面之外的字符
+			if utf16.IsSurrogate(r) && i+6 < len(s) && s[i+1] == '\\' && s[i+2] == 'u' {
+				if low, err := strconv.ParseUint(s[i+3:i+7], 16, 16); err == nil {
+					if combined := utf16.DecodeRune(r, rune(low)); combined != unicode.ReplacementChar {
+						r = combined
+						i += 6
+					}
+				}
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// EscapePropertyKey 转义 properties 的键，使其中的分隔符、空白和注释符号不被误解
+func EscapePropertyKey(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		switch r {
+		case '=', ':', ' ':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '#', '!':
+			if i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			writeEscaped(&b, r)
+		}
+	}
+	return b.String()
+}
+
+// EscapePropertyValue 转义 properties 的值，保留开头的空白
+func EscapePropertyValue(value string) string {
+	var b strings.Builder
+	for i, r := range value {
+		if i == 0 && (r == ' ' || r == '\t') {
+			b.WriteByte('\\')
+			b.WriteRune(r)
+			continue
+		}
+		writeEscaped(&b, r)
+	}
+	return b.String()
+}
+
+func writeEscaped(b *strings.Builder, r rune) {
+	switch r {
+	case '\\':
+		b.WriteString(`\\`)
+	case '\n':
+		b.WriteString(`\n`)
+	case '\r':
+		b.WriteString(`\r`)
+	case '\t':
+		b.WriteString(`\t`)
+	case '\f':
+		b.WriteString(`\f`)
+	default:
+		b.WriteRune(r)
+	}
+}
+
+// 修改 properties 配置中的键，键不存在时追加到末尾。键重复出现时修改生效的最后一次
+func setProperty(content, key, value string) (string, error) {
+	lines := splitLines(content)
+	entries, err := scanProperties(lines)
+	if err != nil {
+		return "", err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Key != key {
+			continue
+		}
+		replaced := append([]string{}, lines[:entry.start]...)
+		replaced = append(replaced, entry.prefix+EscapePropertyValue(value))
+		replaced = append(replaced, lines[entry.end:]...)
+		return strings.Join(replaced, "\n"), nil
+	}
+
+	line := EscapePropertyKey(key) + "=" + EscapePropertyValue(value)
+	if content == "" || strings.HasSuffix(content, "\n") {
+		return content + line + "\n", nil
+	}
+	return content + "\n" + line, nil
+}
+
+// 删除 properties 配置中的键的所有定义
+func unsetProperty(content, key string) (string, error) {
+	lines := splitLines(content)
+	entries, err := scanProperties(lines)
+	if err != nil {
+		return "", err
+	}
+
+	var result []string
+	next := 0
+	found := false
+	for _, entry := range entries {
+		if entry.Key != key {
+			continue
+		}
+		found = true
+		result = append(result, lines[next:entry.start]...)
+		next = entry.end
+	}
+	if !found {
+		return "", fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	result = append(result, lines[next:]...)
+	return strings.Join(result, "\n"), nil
+}
@@ -0,0 +1,72 @@
+package configtree
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Property
+	}{
+		{"separators", "a=1\nb:2\nc 3\nd = 4\ne\t:\t5\n", []Property{{"a", "1"}, {"b", "2"}, {"c", "3"}, {"d", "4"}, {"e", "5"}}},
+		{"comments and blank lines", "# comment\n! comment\n\n  a=1\n", []Property{{"a", "1"}}},
+		{"empty value", "a=\nb\n", []Property{{"a", ""}, {"b", ""}}},
+		{"value keeps separators", "url=jdbc:mysql://db:3306/app?a=b\n", []Property{{"url", "jdbc:mysql://db:3306/app?a=b"}}},
+		{"escaped key", `a\=b\:c\ d=1`, []Property{{"a=b:c d", "1"}}},
+		{"escapes", `a=tab\there\nnew\\slash`, []Property{{"a", "tab\there\nnew\\slash"}}},
+		{"unicode", `name=\u8ba2\u5355`, []Property{{"name", "订单"}}},
+		{"surrogate pair", `emoji=\uD83D\uDE00`, []Property{{"emoji", "😀"}}},
+		{"continuation", "list=a,\\\n    b,\\\n    c\nnext=1", []Property{{"list", "a,b,c"}, {"next", "1"}}},
+		{"escaped backslash at end", "path=C:\\\\\nnext=1", []Property{{"path", `C:\`}, {"next", "1"}}},
+		{"duplicate keys", "a=1\nb=2\na=3\n", []Property{{"a", "3"}, {"b", "2"}}},
+		{"crlf", "a=1\r\nb=2\r\n", []Property{{"a", "1"}, {"b", "2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProperties(tt.content)
+			if err != nil {
+				t.Fatalf("ParseProperties() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProperties() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePropertiesErrors(t *testing.T) {
+	for _, content := range []string{`a=\u12`, `a=\uzzzz`} {
+		if _, err := ParseProperties(content); err == nil {
+			t.Errorf("ParseProperties(%q) succeeded, want error", content)
+		}
+	}
+}
+
+func TestEscapeProperties(t *testing.T) {
+	tests := []struct {
+		key, value string
+	}{
+		{"a=b:c d", "1"},
+		{"#key", "#value"},
+		{"!key", "!value"},
+		{"name", "  leading spaces"},
+		{"multi", "line1\nline2\ttab\\slash"},
+		{"中文", "订单服务"},
+	}
+
+	for _, tt := range tests {
+		content := EscapePropertyKey(tt.key) + "=" + EscapePropertyValue(tt.value)
+		got, err := ParseProperties(content)
+		if err != nil {
+			t.Fatalf("ParseProperties(%q) error: %v", content, err)
+		}
+		want := []Property{{tt.key, tt.value}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseProperties(%q) = %v, want %v", content, got, want)
+		}
+	}
+}
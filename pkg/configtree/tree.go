@@ -0,0 +1,122 @@
+// Package configtree 将 yaml、json、properties 格式的配置解析为键值树，用于按语义比较和转换配置
+package configtree
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported 是否支持解析该类型的配置
+func Supported(configType string) bool {
+	switch configType {
+	case "yaml", "json", "properties":
+		return true
+	default:
+		return false
+	}
+}
+
+// Parse 按配置类型解析配置内容。yaml 和 json 解析为 map、切片和标量组成的树，
+// properties 解析为以完整键名为键的单层 map
+func Parse(configType string, content []byte) (interface{}, error) {
+	switch configType {
+	case "yaml":
+		var value interface{}
+		if err := yaml.Unmarshal(content, &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case "json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			if err == io.EOF {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return value, nil
+	case "properties":
+		properties, err := ParseProperties(string(content))
+		if err != nil {
+			return nil, err
+		}
+		value := make(map[string]interface{}, len(properties))
+		for _, p := range properties {
+			value[p.Key] = p.Value
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("不支持解析 %s 类型的配置", configType)
+	}
+}
+
+// Flatten 将键值树展开为以完整路径为键的单层 map，map 的键以点连接，数组下标写作 [n]
+func Flatten(value interface{}) map[string]string {
+	result := make(map[string]string)
+	flatten("", value, result)
+	return result
+}
+
+func flatten(prefix string, value interface{}, result map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			result[prefix] = "{}"
+		}
+		for key, child := range v {
+			flatten(joinKey(prefix, key), child, result)
+		}
+	case map[interface{}]interface{}:
+		if len(v) == 0 && prefix != "" {
+			result[prefix] = "{}"
+		}
+		for key, child := range v {
+			flatten(joinKey(prefix, fmt.Sprint(key)), child, result)
+		}
+	case []interface{}:
+		if len(v) == 0 && prefix != "" {
+			result[prefix] = "[]"
+		}
+		for i, child := range v {
+			flatten(prefix+"["+strconv.Itoa(i)+"]", child, result)
+		}
+	default:
+		if prefix == "" && value == nil {
+			return
+		}
+		result[prefix] = Scalar(value)
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// Scalar 标量的文本形式，null 写作 null
+func Scalar(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	return fmt.Sprint(value)
+}
+
+// SortedKeys 返回 map 的键，按字典序排列
+func SortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
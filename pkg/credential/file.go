@@ -0,0 +1,229 @@
+package credential
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt 参数，遵循官方推荐的交互式场景取值
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	keyLength     = 32
+	saltLength    = 16
+	formatVersion = 1
+)
+
+// FileStore 使用口令加密的文件存储，适用于没有系统密钥环的环境（如无桌面的 Linux 和 CI）。
+// 文件内容为 AES-256-GCM 加密的 JSON，密钥由口令经 scrypt 派生
+type FileStore struct {
+	Path       string
+	Passphrase func() (string, error)
+
+	mu         sync.Mutex
+	passphrase string
+}
+
+type encryptedFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// NewFileStore 创建加密文件存储
+func NewFileStore(path string, passphrase func() (string, error)) *FileStore {
+	return &FileStore{Path: path, Passphrase: passphrase}
+}
+
+func (s *FileStore) Name() string {
+	return "加密文件 " + s.Path
+}
+
+func (s *FileStore) Get(server, username string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[entryKey(server, username)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (s *FileStore) Set(server, username, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	secrets[entryKey(server, username)] = secret
+	return s.save(secrets)
+}
+
+func (s *FileStore) Delete(server, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	secrets, err := s.load()
+	if err != nil {
+		return err
+	}
+	key := entryKey(server, username)
+	if _, ok := secrets[key]; !ok {
+		return ErrNotFound
+	}
+	delete(secrets, key)
+	return s.save(secrets)
+}
+
+func (s *FileStore) getPassphrase() (string, error) {
+	if s.passphrase != "" {
+		return s.passphrase, nil
+	}
+	if s.Passphrase == nil {
+		return "", errors.New("未提供加密文件的口令")
+	}
+	passphrase, err := s.Passphrase()
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", errors.New("加密文件的口令不能为空")
+	}
+	s.passphrase = passphrase
+	return passphrase, nil
+}
+
+// 读取并解密文件，文件不存在时返回空集合
+func (s *FileStore) load() (map[string]string, error) {
+	secrets := make(map[string]string)
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取凭据文件失败: %w", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("解析凭据文件失败: %w", err)
+	}
+	if file.Version != formatVersion {
+		return nil, fmt.Errorf("不支持的凭据文件版本: %d", file.Version)
+	}
+
+	passphrase, err := s.getPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		// 口令错误时清除缓存，允许调用方重新输入
+		s.passphrase = ""
+		return nil, errors.New("解密凭据文件失败，口令可能不正确")
+	}
+
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("解析凭据文件失败: %w", err)
+	}
+	return secrets, nil
+}
+
+// 加密并原子地写入文件，每次写入都使用新的盐和随机数
+func (s *FileStore) save(secrets map[string]string) error {
+	passphrase, err := s.getPassphrase()
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(encryptedFile{
+		Version: formatVersion,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return fmt.Errorf("创建凭据目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), ".credentials-*")
+	if err != nil {
+		return fmt.Errorf("写入凭据文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入凭据文件失败: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入凭据文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入凭据文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return fmt.Errorf("写入凭据文件失败: %w", err)
+	}
+	return nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("派生密钥失败: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func entryKey(server, username string) string {
+	return username + "@" + server
+}
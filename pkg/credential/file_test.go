@@ -0,0 +1,154 @@
+package credential
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func staticPassphrase(passphrase string) func() (string, error) {
+	return func() (string, error) { return passphrase, nil }
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nacos", "credentials.enc")
+	store := NewFileStore(path, staticPassphrase("correct horse"))
+
+	if _, err := store.Get("http://a:8848", "nacos"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() on missing file error = %v, want ErrNotFound", err)
+	}
+	if err := store.Set("http://a:8848", "nacos", "p1"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if err := store.Set("http://b:8848", "nacos", "p2"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	// 用新的实例重新读取，确认内容已加密写入文件
+	reopened := NewFileStore(path, staticPassphrase("correct horse"))
+	for server, want := range map[string]string{"http://a:8848": "p1", "http://b:8848": "p2"} {
+		got, err := reopened.Get(server, "nacos")
+		if err != nil || got != want {
+			t.Errorf("Get(%s) = %q, %v, want %q", server, got, err, want)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "p1") || strings.Contains(string(data), "nacos@") {
+		t.Errorf("credential file contains plaintext: %s", data)
+	}
+
+	if _, err := reopened.Get("http://a:8848", "other"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() for missing user error = %v, want ErrNotFound", err)
+	}
+	if err := reopened.Delete("http://a:8848", "nacos"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if _, err := reopened.Get("http://a:8848", "nacos"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+	}
+	if err := reopened.Delete("http://a:8848", "nacos"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() twice error = %v, want ErrNotFound", err)
+	}
+	if got, err := store.Get("http://b:8848", "nacos"); err != nil || got != "p2" {
+		t.Errorf("Get() after deleting another entry = %q, %v, want p2", got, err)
+	}
+}
+
+func TestFileStorePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 不使用 Unix 文件权限")
+	}
+	dir := filepath.Join(t.TempDir(), "nacos")
+	path := filepath.Join(dir, "credentials.enc")
+	store := NewFileStore(path, staticPassphrase("secret"))
+	if err := store.Set("http://a:8848", "nacos", "p1"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	for name, want := range map[string]os.FileMode{path: 0600, dir: 0700} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s mode = %o, want %o", name, got, want)
+		}
+	}
+	// 不留下临时文件
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the credential file", len(entries))
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	if err := NewFileStore(path, staticPassphrase("right")).Set("http://a:8848", "nacos", "p1"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+
+	// 口令错误后重新询问口令
+	attempts := []string{"wrong", "right"}
+	calls := 0
+	store := NewFileStore(path, func() (string, error) {
+		passphrase := attempts[calls]
+		calls++
+		return passphrase, nil
+	})
+	if _, err := store.Get("http://a:8848", "nacos"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() with wrong passphrase error = %v, want decryption error", err)
+	}
+	if got, err := store.Get("http://a:8848", "nacos"); err != nil || got != "p1" {
+		t.Errorf("Get() after retry = %q, %v, want p1", got, err)
+	}
+	if calls != 2 {
+		t.Errorf("passphrase asked %d times, want 2", calls)
+	}
+
+	if _, err := NewFileStore(path, staticPassphrase("wrong")).Get("http://a:8848", "nacos"); err == nil {
+		t.Error("Get() with wrong passphrase succeeded")
+	}
+}
+
+func TestFileStorePassphraseErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	if err := NewFileStore(path, nil).Set("s", "u", "p"); err == nil {
+		t.Error("Set() without passphrase succeeded")
+	}
+	if err := NewFileStore(path, staticPassphrase("")).Set("s", "u", "p"); err == nil {
+		t.Error("Set() with empty passphrase succeeded")
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("credential file created without passphrase: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path, staticPassphrase("x")).Get("s", "u"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() on corrupt file error = %v, want parse error", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	store, err := Open(TypeFile, Options{FilePath: path, Passphrase: staticPassphrase("x")})
+	if err != nil {
+		t.Fatalf("Open(file) error: %v", err)
+	}
+	if _, ok := store.(*FileStore); !ok {
+		t.Errorf("Open(file) = %T, want *FileStore", store)
+	}
+	if _, err := Open("vault", Options{}); err == nil {
+		t.Error("Open(vault) succeeded, want error")
+	}
+}
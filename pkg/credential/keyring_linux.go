@@ -0,0 +1,194 @@
+//go:build linux
+
+package credential
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Secret Service D-Bus API，参见 https://specifications.freedesktop.org/secret-service/
+const (
+	secretServiceName      = "org.freedesktop.secrets"
+	secretServicePath      = "/org/freedesktop/secrets"
+	secretServiceInterface = "org.freedesktop.Secret.Service"
+	collectionInterface    = "org.freedesktop.Secret.Collection"
+	itemInterface          = "org.freedesktop.Secret.Item"
+	promptInterface        = "org.freedesktop.Secret.Prompt"
+	applicationAttribute   = "nacos-cli"
+)
+
+// secret 对应 Secret Service 中的 (oayays) 结构
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// KeyringStore 通过 Secret Service D-Bus API 使用系统密钥环（GNOME Keyring、KWallet 等）
+type KeyringStore struct {
+	conn    *dbus.Conn
+	service dbus.BusObject
+	session dbus.ObjectPath
+}
+
+// NewKeyringStore 连接会话总线上的 Secret Service，不可用时返回错误
+func NewKeyringStore() (*KeyringStore, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("系统密钥环不可用: %w", err)
+	}
+
+	service := conn.Object(secretServiceName, secretServicePath)
+
+	// 凭据只在本机会话总线上传输，使用明文会话即可
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	err = service.Call(secretServiceInterface+".OpenSession", 0, "plain", dbus.MakeVariant("")).Store(&output, &session)
+	if err != nil {
+		return nil, fmt.Errorf("系统密钥环不可用: %w", err)
+	}
+
+	return &KeyringStore{conn: conn, service: service, session: session}, nil
+}
+
+func (s *KeyringStore) Name() string {
+	return "系统密钥环"
+}
+
+func (s *KeyringStore) Get(server, username string) (string, error) {
+	item, err := s.findItem(server, username)
+	if err != nil {
+		return "", err
+	}
+
+	var sec secret
+	if err := s.conn.Object(secretServiceName, item).Call(itemInterface+".GetSecret", 0, s.session).Store(&sec); err != nil {
+		return "", fmt.Errorf("读取密钥环失败: %w", err)
+	}
+	return string(sec.Value), nil
+}
+
+func (s *KeyringStore) Set(server, username, value string) error {
+	var collection dbus.ObjectPath
+	if err := s.service.Call(secretServiceInterface+".ReadAlias", 0, "default").Store(&collection); err != nil {
+		return fmt.Errorf("读取默认密钥环失败: %w", err)
+	}
+	if collection == "/" {
+		return errors.New("没有默认密钥环，请先在系统中创建")
+	}
+	if err := s.unlock([]dbus.ObjectPath{collection}); err != nil {
+		return err
+	}
+
+	properties := map[string]dbus.Variant{
+		itemInterface + ".Label":      dbus.MakeVariant(fmt.Sprintf("nacos-cli: %s@%s", username, server)),
+		itemInterface + ".Attributes": dbus.MakeVariant(attributes(server, username)),
+	}
+	sec := secret{
+		Session:     s.session,
+		Parameters:  []byte{},
+		Value:       []byte(value),
+		ContentType: "text/plain; charset=utf8",
+	}
+
+	var item, prompt dbus.ObjectPath
+	err := s.conn.Object(secretServiceName, collection).
+		Call(collectionInterface+".CreateItem", 0, properties, sec, true).Store(&item, &prompt)
+	if err != nil {
+		return fmt.Errorf("写入密钥环失败: %w", err)
+	}
+	return s.prompt(prompt)
+}
+
+func (s *KeyringStore) Delete(server, username string) error {
+	item, err := s.findItem(server, username)
+	if err != nil {
+		return err
+	}
+
+	var prompt dbus.ObjectPath
+	if err := s.conn.Object(secretServiceName, item).Call(itemInterface+".Delete", 0).Store(&prompt); err != nil {
+		return fmt.Errorf("删除密钥环条目失败: %w", err)
+	}
+	return s.prompt(prompt)
+}
+
+// 按属性查找条目，已锁定的条目会先解锁
+func (s *KeyringStore) findItem(server, username string) (dbus.ObjectPath, error) {
+	var unlocked, locked []dbus.ObjectPath
+	err := s.service.Call(secretServiceInterface+".SearchItems", 0, attributes(server, username)).Store(&unlocked, &locked)
+	if err != nil {
+		return "", fmt.Errorf("查询密钥环失败: %w", err)
+	}
+
+	if len(unlocked) > 0 {
+		return unlocked[0], nil
+	}
+	if len(locked) == 0 {
+		return "", ErrNotFound
+	}
+	if err := s.unlock(locked[:1]); err != nil {
+		return "", err
+	}
+	return locked[0], nil
+}
+
+func (s *KeyringStore) unlock(objects []dbus.ObjectPath) error {
+	var unlocked []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	if err := s.service.Call(secretServiceInterface+".Unlock", 0, objects).Store(&unlocked, &prompt); err != nil {
+		return fmt.Errorf("解锁密钥环失败: %w", err)
+	}
+	return s.prompt(prompt)
+}
+
+// 需要用户交互（如输入密钥环密码）时，服务端返回 prompt 对象，等待其完成
+func (s *KeyringStore) prompt(prompt dbus.ObjectPath) error {
+	if prompt == "/" || prompt == "" {
+		return nil
+	}
+
+	if err := s.conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(prompt),
+		dbus.WithMatchInterface(promptInterface),
+		dbus.WithMatchMember("Completed"),
+	); err != nil {
+		return fmt.Errorf("等待密钥环确认失败: %w", err)
+	}
+	defer s.conn.RemoveMatchSignal(
+		dbus.WithMatchObjectPath(prompt),
+		dbus.WithMatchInterface(promptInterface),
+		dbus.WithMatchMember("Completed"),
+	)
+
+	signals := make(chan *dbus.Signal, 1)
+	s.conn.Signal(signals)
+	defer s.conn.RemoveSignal(signals)
+
+	if err := s.conn.Object(secretServiceName, prompt).Call(promptInterface+".Prompt", 0, "").Err; err != nil {
+		return fmt.Errorf("等待密钥环确认失败: %w", err)
+	}
+
+	for signal := range signals {
+		if signal.Path != prompt || signal.Name != promptInterface+".Completed" {
+			continue
+		}
+		if len(signal.Body) > 0 && signal.Body[0] == true {
+			return errors.New("密钥环操作已被取消")
+		}
+		return nil
+	}
+	return errors.New("等待密钥环确认失败")
+}
+
+func attributes(server, username string) map[string]string {
+	return map[string]string{
+		"application": applicationAttribute,
+		"server":      server,
+		"username":    username,
+	}
+}
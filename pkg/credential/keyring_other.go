@@ -0,0 +1,29 @@
+//go:build !linux
+
+package credential
+
+import "errors"
+
+// KeyringStore 当前平台不支持系统密钥环
+type KeyringStore struct{}
+
+// NewKeyringStore 当前平台只支持 Linux 上的 Secret Service，其他平台返回错误
+func NewKeyringStore() (*KeyringStore, error) {
+	return nil, errors.New("当前平台不支持系统密钥环")
+}
+
+func (s *KeyringStore) Name() string {
+	return "系统密钥环"
+}
+
+func (s *KeyringStore) Get(server, username string) (string, error) {
+	return "", errors.New("当前平台不支持系统密钥环")
+}
+
+func (s *KeyringStore) Set(server, username, secret string) error {
+	return errors.New("当前平台不支持系统密钥环")
+}
+
+func (s *KeyringStore) Delete(server, username string) error {
+	return errors.New("当前平台不支持系统密钥环")
+}
@@ -0,0 +1,54 @@
+package credential
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound 存储中没有对应的凭据
+var ErrNotFound = errors.New("凭据不存在")
+
+// Store 按服务器地址和用户名保存密码等敏感信息
+type Store interface {
+	// Name 返回存储的名称，用于提示信息
+	Name() string
+	Get(server, username string) (string, error)
+	Set(server, username, secret string) error
+	Delete(server, username string) error
+}
+
+// 支持的存储类型
+const (
+	TypeAuto    = "auto"
+	TypeKeyring = "keyring"
+	TypeFile    = "file"
+)
+
+// Options 创建存储所需的参数
+type Options struct {
+	// FilePath 加密文件的路径
+	FilePath string
+	// Passphrase 返回加密文件的口令，只在首次读写加密文件时调用
+	Passphrase func() (string, error)
+}
+
+// Open 按类型打开存储。auto 优先使用系统密钥环，不可用时回退到加密文件
+func Open(storeType string, opts Options) (Store, error) {
+	switch storeType {
+	case TypeKeyring:
+		store, err := NewKeyringStore()
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case TypeFile:
+		return NewFileStore(opts.FilePath, opts.Passphrase), nil
+	case TypeAuto, "":
+		if store, err := NewKeyringStore(); err == nil {
+			return store, nil
+		}
+		return NewFileStore(opts.FilePath, opts.Passphrase), nil
+	default:
+		return nil, fmt.Errorf("不支持的凭据存储类型: %s，可选值: auto, keyring, file", storeType)
+	}
+}
@@ -0,0 +1,297 @@
+// Package diff 逐行比较文本，输出统一格式（unified）的差异；也可以按键比较两组键值
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Op 编辑操作的类型
+type Op byte
+
+const (
+	Equal  Op = ' '
+	Delete Op = '-'
+	Insert Op = '+'
+	Modify Op = '~'
+)
+
+// Edit 一行的编辑操作
+type Edit struct {
+	Op   Op
+	Line string
+}
+
+// Lines 将文本拆分为行，末尾的换行符不产生空行
+func Lines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Compute 用 Myers 算法计算从 a 到 b 的最短编辑序列。
+// 使用线性空间的变体：从两端同时搜索，找到最短路径中间的一点后分别比较两侧，内存为 O(n+m)
+func Compute(a, b []string) []Edit {
+	return compare(make([]Edit, 0, len(a)+len(b)), a, b)
+}
+
+// 比较 a 和 b，将编辑序列追加到 edits 之后
+func compare(edits []Edit, a, b []string) []Edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		edits = append(edits, Edit{Op: Equal, Line: a[prefix]})
+		prefix++
+	}
+	a, b = a[prefix:], b[prefix:]
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			edits = append(edits, Edit{Op: Insert, Line: line})
+		}
+	case len(b) == 0:
+		for _, line := range a {
+			edits = append(edits, Edit{Op: Delete, Line: line})
+		}
+	default:
+		if x, y, ok := middle(a, b); ok {
+			edits = compare(edits, a[:x], b[:y])
+			edits = compare(edits, a[x:], b[y:])
+		} else {
+			// 没有相同的行
+			for _, line := range a {
+				edits = append(edits, Edit{Op: Delete, Line: line})
+			}
+			for _, line := range b {
+				edits = append(edits, Edit{Op: Insert, Line: line})
+			}
+		}
+	}
+
+	for _, line := range common {
+		edits = append(edits, Edit{Op: Equal, Line: line})
+	}
+	return edits
+}
+
+// 从起点和终点同时搜索最短编辑路径，返回两个方向的路径重合处的一点 (x, y)。
+// a、b 都不为空且首尾行不同，因此该点既不是起点也不是终点；没有相同的行时 ok 为 false
+func middle(a, b []string) (x, y int, ok bool) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	// forward[k] 为正向第 d 步在对角线 k 上到达的最远 x，backward 为反向（从终点倒着走）的距离
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+
+	delta := n - m
+	// 两个方向的对角线奇偶性不同时在正向搜索中检查重合，否则在反向搜索中检查
+	checkForward := delta%2 != 0
+	// 越出边界的对角线不再搜索
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[i] = x
+			switch {
+			case x > n:
+				fEnd += 2
+			case y > m:
+				fStart += 2
+			case checkForward:
+				j := offset + delta - k
+				if j >= 0 && j < len(backward) && backward[j] != -1 && x >= n-backward[j] {
+					return x, y, true
+				}
+			}
+		}
+
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x = backward[i+1]
+			} else {
+				x = backward[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+			backward[i] = x
+			switch {
+			case x > n:
+				bEnd += 2
+			case y > m:
+				bStart += 2
+			case !checkForward:
+				j := offset + delta - k
+				if j >= 0 && j < len(forward) && forward[j] != -1 && forward[j] >= n-x {
+					fx := forward[j]
+					return fx, fx - (j - offset), true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Unified 返回统一格式的差异，每个变更块前后保留 context 行上下文，内容相同时返回空字符串
+func Unified(fromName, toName, a, b string, context int) string {
+	edits := Compute(Lines(a), Lines(b))
+
+	// 每个编辑操作之前已经处理的行数，用于计算变更块的行号
+	aPos := make([]int, len(edits)+1)
+	bPos := make([]int, len(edits)+1)
+	for i, e := range edits {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if e.Op != Insert {
+			aPos[i+1]++
+		}
+		if e.Op != Delete {
+			bPos[i+1]++
+		}
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			i++
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(edits) {
+			if edits[end].Op != Equal {
+				end++
+				continue
+			}
+			j := end
+			for j < len(edits) && edits[j].Op == Equal {
+				j++
+			}
+			// 两处变更之间的相同行不超过 2*context 时合并为一个变更块
+			if j == len(edits) || j-end > 2*context {
+				end += context
+				if end > len(edits) {
+					end = len(edits)
+				}
+				break
+			}
+			end = j
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(aPos[start], aPos[end]-aPos[start]), hunkRange(bPos[start], bPos[end]-bPos[start]))
+		for _, e := range edits[start:end] {
+			out.WriteByte(byte(e.Op))
+			out.WriteString(e.Line)
+			out.WriteByte('\n')
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// 变更块的行号范围，没有行时行号为变更块之前的一行
+func hunkRange(pos, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", pos+1)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+// Change 一个键的变化
+type Change struct {
+	Op  Op
+	Key string
+	Old string
+	New string
+}
+
+// Keys 按键比较两组键值，返回删除、新增和修改的键，按键名排序
+func Keys(a, b map[string]string) []Change {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []Change
+	for _, key := range keys {
+		oldValue, inA := a[key]
+		newValue, inB := b[key]
+		switch {
+		case !inB:
+			changes = append(changes, Change{Op: Delete, Key: key, Old: oldValue})
+		case !inA:
+			changes = append(changes, Change{Op: Insert, Key: key, New: newValue})
+		case oldValue != newValue:
+			changes = append(changes, Change{Op: Modify, Key: key, Old: oldValue, New: newValue})
+		}
+	}
+	return changes
+}
+
+// FormatKeys 以文本形式输出键的变化，没有变化时返回空字符串
+func FormatKeys(fromName, toName string, changes []Change) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for _, c := range changes {
+		switch c.Op {
+		case Delete:
+			fmt.Fprintf(&b, "- %s: %s\n", c.Key, c.Old)
+		case Insert:
+			fmt.Fprintf(&b, "+ %s: %s\n", c.Key, c.New)
+		case Modify:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", c.Key, c.Old, c.New)
+		}
+	}
+	return b.String()
+}
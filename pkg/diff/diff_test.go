@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// 从编辑序列还原两侧的行，并统计插入和删除的行数
+func applyEdits(edits []Edit) (a, b []string, changes int) {
+	for _, e := range edits {
+		switch e.Op {
+		case Equal:
+			a = append(a, e.Line)
+			b = append(b, e.Line)
+		case Delete:
+			a = append(a, e.Line)
+			changes++
+		case Insert:
+			b = append(b, e.Line)
+			changes++
+		}
+	}
+	return a, b, changes
+}
+
+// 按最长公共子序列计算最少的插入和删除行数
+func minChanges(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] > lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	return len(a) + len(b) - 2*lcs[0][0]
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"equal", "a b c", "a b c", " a b c"},
+		{"empty", "", "", ""},
+		{"insert all", "", "a b", "+a+b"},
+		{"delete all", "a b", "", "-a-b"},
+		{"modify middle", "a b c", "a x c", " a-b+x c"},
+		{"insert middle", "a c", "a b c", " a+b c"},
+		{"delete end", "a b c", "a b", " a b-c"},
+		{"no common lines", "a b", "c d", "-a-b+c+d"},
+		{"move", "a b c d", "b c d a", "-a b c d+a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			for _, e := range Compute(strings.Fields(tt.a), strings.Fields(tt.b)) {
+				b.WriteByte(byte(e.Op))
+				b.WriteString(e.Line)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("Compute(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeMinimal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, r.Intn(30))
+		for i := range lines {
+			lines[i] = fmt.Sprint(r.Intn(4))
+		}
+		return lines
+	}
+
+	for i := 0; i < 2000; i++ {
+		a, b := randomLines(), randomLines()
+		gotA, gotB, changes := applyEdits(Compute(a, b))
+		if !reflect.DeepEqual(gotA, a) && !(len(gotA) == 0 && len(a) == 0) {
+			t.Fatalf("Compute(%v, %v) does not keep a: %v", a, b, gotA)
+		}
+		if !reflect.DeepEqual(gotB, b) && !(len(gotB) == 0 && len(b) == 0) {
+			t.Fatalf("Compute(%v, %v) does not produce b: %v", a, b, gotB)
+		}
+		if want := minChanges(a, b); changes != want {
+			t.Fatalf("Compute(%v, %v) has %d changes, want %d", a, b, changes, want)
+		}
+	}
+}
+
+func TestComputeLargeInput(t *testing.T) {
+	// 完全不同的两个大文件，保存每一步的搜索状态时需要约 1GB 内存
+	a := make([]string, 4000)
+	b := make([]string, 4000)
+	for i := range a {
+		a[i] = fmt.Sprintf("a%d", i)
+		b[i] = fmt.Sprintf("b%d", i)
+	}
+	b[2000] = a[2000]
+
+	_, _, changes := applyEdits(Compute(a, b))
+	if changes != 7998 {
+		t.Errorf("Compute() has %d changes, want 7998", changes)
+	}
+}
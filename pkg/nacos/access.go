@@ -0,0 +1,97 @@
+package nacos
+
+import (
+	"regexp"
+	"strings"
+)
+
+// UserRoles 返回用户绑定的所有角色。该接口在服务端需要管理员权限
+func (c *Client) UserRoles(username string) ([]string, error) {
+	const pageSize = 100
+	var roles []string
+	for pageNo := 1; ; pageNo++ {
+		page, err := c.ListRoles(pageNo, pageSize, username, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, binding := range page.PageItems {
+			roles = append(roles, binding.Role)
+		}
+		if len(page.PageItems) < pageSize {
+			break
+		}
+	}
+	return roles, nil
+}
+
+// RolePermissions 返回多个角色的所有权限
+func (c *Client) RolePermissions(roles []string) ([]Permission, error) {
+	const pageSize = 100
+	var permissions []Permission
+	for _, role := range roles {
+		for pageNo := 1; ; pageNo++ {
+			page, err := c.ListPermissions(pageNo, pageSize, role)
+			if err != nil {
+				return nil, err
+			}
+			permissions = append(permissions, page.PageItems...)
+			if len(page.PageItems) < pageSize {
+				break
+			}
+		}
+	}
+	return permissions, nil
+}
+
+// AccessReview 用户的身份和权限，用于在客户端判断能否访问资源
+type AccessReview struct {
+	Username    string       `json:"username"`
+	GlobalAdmin bool         `json:"globalAdmin"`
+	Roles       []string     `json:"roles"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Can 判断是否允许对资源执行动作。resource 格式同 NormalizeResource，省略的部分表示全部，
+// 例如 dev 要求拥有整个 dev 命名空间的权限；action 为 r 或 w
+func (r *AccessReview) Can(action, resource string) bool {
+	if r.GlobalAdmin {
+		return true
+	}
+	for _, role := range r.Roles {
+		if role == AdminRole {
+			return true
+		}
+	}
+
+	target, err := NormalizeResource(resource)
+	if err != nil {
+		return false
+	}
+	target = normalizePublic(target)
+	for _, p := range r.Permissions {
+		if MatchPermission(p, action, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPermission 与服务端的判断方式一致：权限动作包含请求的动作，
+// 且将权限资源中的 * 视为任意字符后能匹配整个资源
+func MatchPermission(p Permission, action, resource string) bool {
+	if !strings.Contains(p.Action, action) {
+		return false
+	}
+	pattern := regexp.QuoteMeta(normalizePublic(p.Resource))
+	pattern = "^" + strings.ReplaceAll(pattern, `\*`, ".*") + "$"
+	matched, err := regexp.MatchString(pattern, normalizePublic(resource))
+	return err == nil && matched
+}
+
+// 默认命名空间的ID为空，public 与空等价
+func normalizePublic(resource string) string {
+	if strings.HasPrefix(resource, "public:") {
+		return strings.TrimPrefix(resource, "public")
+	}
+	return resource
+}
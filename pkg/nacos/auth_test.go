@@ -0,0 +1,90 @@
+package nacos
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSignHmacSHA1(t *testing.T) {
+	// RFC 2202 测试用例 2
+	got := SignHmacSHA1("what do ya want for nothing?", "Jefe")
+	if want := "7/zfauXrL6LSdBbV8YTfnCWafHk="; got != want {
+		t.Errorf("SignHmacSHA1() = %s, want %s", got, want)
+	}
+}
+
+func TestConfigSignData(t *testing.T) {
+	tests := []struct {
+		tenant, group, want string
+	}{
+		{"dev", "DEFAULT_GROUP", "dev+DEFAULT_GROUP+1700000000000"},
+		{"", "DEFAULT_GROUP", "DEFAULT_GROUP+1700000000000"},
+		{"dev", "", "dev+1700000000000"},
+		{"dev", " ", "dev+1700000000000"},
+		{"", "", "1700000000000"},
+	}
+	for _, tt := range tests {
+		if got := ConfigSignData(tt.tenant, tt.group, "1700000000000"); got != tt.want {
+			t.Errorf("ConfigSignData(%q, %q) = %s, want %s", tt.tenant, tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestNamingSignData(t *testing.T) {
+	tests := []struct {
+		group, service, want string
+	}{
+		{"DEFAULT_GROUP", "order", "1700000000000@@DEFAULT_GROUP@@order"},
+		{"DEFAULT_GROUP", "DEFAULT_GROUP@@order", "1700000000000@@DEFAULT_GROUP@@order"},
+		{"", "order", "1700000000000@@order"},
+		{"DEFAULT_GROUP", "", "1700000000000"},
+	}
+	for _, tt := range tests {
+		if got := NamingSignData(tt.group, tt.service, "1700000000000"); got != tt.want {
+			t.Errorf("NamingSignData(%q, %q) = %s, want %s", tt.group, tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestAccessKeyAuthApply(t *testing.T) {
+	auth := &AccessKeyAuth{
+		AccessKey: "ak",
+		SecretKey: "sk",
+		Now:       func() time.Time { return time.UnixMilli(1700000000000) },
+	}
+
+	tests := []struct {
+		name   string
+		params url.Values
+		want   string
+	}{
+		{"tenant and group", url.Values{"tenant": {"dev"}, "group": {"DEFAULT_GROUP"}}, "KL/M5t10kwFO20XBbQMlGRddmhE="},
+		// 在命名空间中列出配置时分组为空
+		{"tenant only", url.Values{"tenant": {"dev"}, "group": {""}}, "FDx7pRYaklgaPVOfpcNL/5pYt+A="},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if err := auth.Apply(&Client{}, "/nacos/v1/cs/configs", tt.params, header); err != nil {
+			t.Fatalf("%s: Apply() error = %v", tt.name, err)
+		}
+		if header.Get("Spas-AccessKey") != "ak" || header.Get("Timestamp") != "1700000000000" {
+			t.Errorf("%s: headers = %v", tt.name, header)
+		}
+		if got := header.Get("Spas-Signature"); got != tt.want {
+			t.Errorf("%s: Spas-Signature = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	params := url.Values{"groupName": {"DEFAULT_GROUP"}, "serviceName": {"order"}}
+	if err := auth.Apply(&Client{}, "/nacos/v1/ns/instance/list", params, http.Header{}); err != nil {
+		t.Fatalf("naming: Apply() error = %v", err)
+	}
+	if params.Get("ak") != "ak" || params.Get("data") != "1700000000000@@DEFAULT_GROUP@@order" {
+		t.Errorf("naming params = %v", params)
+	}
+	if got, want := params.Get("signature"), "yq65guUEhno/N/s0pnNMJHPkbwg="; got != want {
+		t.Errorf("naming signature = %s, want %s", got, want)
+	}
+}
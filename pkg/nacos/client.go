@@ -2,28 +2,63 @@ package nacos
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
 	"time"
 )
 
 type Client struct {
-	ServerURL   string
-	Username    string
-	Password    string
-	Namespace   string
-	Token       string
-	TokenExpiry int64 // Unix时间戳，表示token过期时间
+	ServerURL    string
+	Username     string
+	Password     string
+	PasswordFunc func() string // Password 为空时在登录前调用以获取密码，避免使用缓存令牌时也读取凭据存储
+	Namespace    string
+	Token        string
+	TokenExpiry  int64         // Unix时间戳，表示token过期时间
+	Debug        bool          // 输出调试日志，令牌和密码会被隐藏
+	Auth         Authenticator // 鉴权方式，为空时使用用户名密码登录
+	Headers      http.Header   // 每个请求（包括登录）都附加的请求头，如服务身份标识和网关鉴权头
+	HTTPClient   *http.Client  // 发送请求使用的HTTP客户端，为空时使用 http.DefaultClient
 }
 
 type Config struct {
-	DataID  string `json:"dataId"`
-	Group   string `json:"group"`
-	Content string `json:"content"`
-	Type    string `json:"type"`
+	DataID     string `json:"dataId"`
+	Group      string `json:"group"`
+	Content    string `json:"content"`
+	Type       string `json:"type"`
+	Tenant     string `json:"tenant,omitempty"`
+	AppName    string `json:"appName,omitempty"`
+	MD5        string `json:"md5,omitempty"`
+	Desc       string `json:"desc,omitempty"`
+	ConfigTags string `json:"configTags,omitempty"`
+	CreateUser string `json:"createUser,omitempty"`
+	CreateTime int64  `json:"createTime,omitempty"` // 毫秒时间戳
+	ModifyTime int64  `json:"modifyTime,omitempty"` // 毫秒时间戳
+}
+
+// InferConfigType 根据配置ID的扩展名推断配置类型，无法推断时返回 text
+func InferConfigType(dataID string) string {
+	switch strings.ToLower(path.Ext(dataID)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".properties":
+		return "properties"
+	case ".json":
+		return "json"
+	case ".xml":
+		return "xml"
+	case ".toml":
+		return "toml"
+	case ".html", ".htm":
+		return "html"
+	default:
+		return "text"
+	}
 }
 
 type Namespace struct {
@@ -41,6 +76,17 @@ type LoginResponse struct {
 	GlobalAdmin bool   `json:"globalAdmin"`
 }
 
+// APIError 服务端返回了非200状态码
+type APIError struct {
+	Action     string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s失败，状态码: %d, 响应: %s", e.Action, e.StatusCode, e.Body)
+}
+
 func NewClient(serverURL, username, password, namespace string) *Client {
 	return &Client{
 		ServerURL:   strings.TrimSuffix(serverURL, "/"),
@@ -52,6 +98,7 @@ func NewClient(serverURL, username, password, namespace string) *Client {
 	}
 }
 
+// Login 按客户端的鉴权方式获取访问凭据，签名鉴权和无鉴权方式不需要登录
 func (c *Client) Login() (*LoginResponse, error) {
 	if strings.TrimSpace(c.ServerURL) == "" {
 		return nil, fmt.Errorf("服务器地址未设置，请先运行 'nacos-cli user server <url>'")
@@ -59,8 +106,24 @@ func (c *Client) Login() (*LoginResponse, error) {
 	if !strings.HasPrefix(c.ServerURL, "http://") && !strings.HasPrefix(c.ServerURL, "https://") {
 		return nil, fmt.Errorf("服务器地址缺少协议前缀，请使用 http:// 或 https://")
 	}
+	return c.authenticator().Login(c)
+}
+
+func (c *Client) authenticator() Authenticator {
+	if c.Auth == nil {
+		return &PasswordAuth{}
+	}
+	return c.Auth
+}
+
+// 使用用户名密码登录，获取的令牌保存在 Token 和 TokenExpiry 中
+func (c *Client) loginWithPassword() (*LoginResponse, error) {
+	if c.Password == "" && c.PasswordFunc != nil {
+		c.Password = c.PasswordFunc()
+		c.PasswordFunc = nil
+	}
 	if strings.TrimSpace(c.Username) == "" || strings.TrimSpace(c.Password) == "" {
-		return nil, fmt.Errorf("用户名或密码未设置，请先运行 'nacos-cli user set <username> <password>'")
+		return nil, fmt.Errorf("用户名或密码未设置，请先运行 'nacos-cli user set <username>'")
 	}
 
 	data := url.Values{}
@@ -68,33 +131,9 @@ func (c *Client) Login() (*LoginResponse, error) {
 	data.Set("password", c.Password)
 
 	// 使用Nacos v1 API
-	loginURL := c.ServerURL + "/nacos/v1/auth/users/login"
-
-	// 创建请求
-	req, err := http.NewRequest("POST", loginURL, strings.NewReader(data.Encode()))
+	body, err := c.send(http.MethodPost, "/nacos/v1/auth/users/login", data, http.Header{}, "登录")
 	if err != nil {
-		return nil, fmt.Errorf("创建登录请求失败: %w", err)
-	}
-
-	// 设置请求头
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("User-Agent", "nacos-cli")
-
-	// 发送请求
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("登录失败: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
-	}
-
-	// 检查HTTP状态码
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("登录失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+		return nil, err
 	}
 
 	var loginResp LoginResponse
@@ -113,6 +152,9 @@ func (c *Client) Login() (*LoginResponse, error) {
 	return &loginResp, nil
 }
 
+// ErrConfigNotFound 配置不存在
+var ErrConfigNotFound = errors.New("配置不存在")
+
 func (c *Client) GetConfig(dataID, group string) (*Config, error) {
 	params := url.Values{}
 	params.Set("dataId", dataID)
@@ -120,46 +162,72 @@ func (c *Client) GetConfig(dataID, group string) (*Config, error) {
 	if c.Namespace != "" {
 		params.Set("tenant", c.Namespace)
 	}
-	if c.Token != "" {
-		params.Set("accessToken", c.Token)
-	}
 
 	// 使用Nacos v1 API
-	url := c.ServerURL + "/nacos/v1/cs/configs?" + params.Encode()
-	fmt.Printf("获取配置URL: %s\n", url)
-
-	req, err := http.NewRequest("GET", url, nil)
+	content, err := c.doRequest(http.MethodGet, "/nacos/v1/cs/configs", params, "获取配置")
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
+		return nil, ErrConfigNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if c.Token != "" {
-		req.Header.Set("accessToken", c.Token)
-	}
+	return &Config{
+		DataID:  dataID,
+		Group:   group,
+		Content: string(content),
+	}, nil
+}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("获取配置失败: %w", err)
+// GetConfigDetail 获取配置及其元数据，包括MD5、描述、标签、创建和修改时间
+func (c *Client) GetConfigDetail(dataID, group string) (*Config, error) {
+	params := url.Values{}
+	params.Set("dataId", dataID)
+	params.Set("group", group)
+	params.Set("show", "all")
+	if c.Namespace != "" {
+		params.Set("tenant", c.Namespace)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		return nil, fmt.Errorf("配置不存在")
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/cs/configs", params, "获取配置")
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == 404 {
+		return nil, ErrConfigNotFound
 	}
-
-	content, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
+	// 配置不存在时服务端返回空响应
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil, ErrConfigNotFound
+	}
 
-	return &Config{
-		DataID:  dataID,
-		Group:   group,
-		Content: string(content),
-	}, nil
+	var config Config
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %w, 原始响应: %s", err, string(body))
+	}
+	return &config, nil
 }
 
 func (c *Client) PublishConfig(config *Config) error {
+	return c.publishConfig(config, "")
+}
+
+// ErrConfigConflict 条件发布时服务端的配置已被修改
+var ErrConfigConflict = errors.New("配置已被其他人修改")
+
+// 条件发布失败时服务端返回的错误信息，如 "Cas publish fail, server md5 may have changed."
+const casConflictMessage = "server md5 may have changed"
+
+// PublishConfigCas 仅当服务端配置的MD5仍为 casMd5 时发布配置，否则返回 ErrConfigConflict。
+// 配置的描述、标签和应用名会一并提交，避免发布时被清空。
+// casMd5 通过请求头传递，不支持条件发布的旧版本服务端会忽略它，调用方需要自行在发布前检查MD5
+func (c *Client) PublishConfigCas(config *Config, casMd5 string) error {
+	return c.publishConfig(config, casMd5)
+}
+
+func (c *Client) publishConfig(config *Config, casMd5 string) error {
 	data := url.Values{}
 	data.Set("dataId", config.DataID)
 	data.Set("group", config.Group)
@@ -167,38 +235,40 @@ func (c *Client) PublishConfig(config *Config) error {
 	if config.Type != "" {
 		data.Set("type", config.Type)
 	}
+	if config.AppName != "" {
+		data.Set("appName", config.AppName)
+	}
+	if config.Desc != "" {
+		data.Set("desc", config.Desc)
+	}
+	if config.ConfigTags != "" {
+		data.Set("config_tags", config.ConfigTags)
+	}
 	if c.Namespace != "" {
 		data.Set("tenant", c.Namespace)
 	}
-	if c.Token != "" {
-		data.Set("accessToken", c.Token)
+	header := http.Header{}
+	if casMd5 != "" {
+		// 服务端从请求头而不是表单中读取 casMd5
+		header.Set("casMd5", casMd5)
 	}
 
 	// 使用Nacos v1 API
-	url := c.ServerURL + "/nacos/v1/cs/configs"
-	fmt.Printf("发布配置URL: %s\n", url)
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(data.Encode()))
-	if err != nil {
-		return err
+	body, err := c.doRequestWithHeader(http.MethodPost, "/nacos/v1/cs/configs", data, header, "发布配置")
+	var apiErr *APIError
+	if casMd5 != "" && errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusConflict || strings.Contains(apiErr.Body, casConflictMessage)) {
+		return ErrConfigConflict
 	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	if c.Token != "" {
-		req.Header.Set("accessToken", c.Token)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("发布配置失败: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("发布配置失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+	if strings.TrimSpace(string(body)) == "false" {
+		if casMd5 != "" {
+			return ErrConfigConflict
+		}
+		return fmt.Errorf("发布配置失败，服务端返回: %s", string(body))
 	}
-
 	return nil
 }
 
@@ -209,39 +279,14 @@ func (c *Client) DeleteConfig(dataID, group string) error {
 	if c.Namespace != "" {
 		params.Set("tenant", c.Namespace)
 	}
-	if c.Token != "" {
-		params.Set("accessToken", c.Token)
-	}
 
 	// 使用Nacos v1 API
-	url := c.ServerURL + "/nacos/v1/cs/configs?" + params.Encode()
-	fmt.Printf("删除配置URL: %s\n", url)
-
-	req, err := http.NewRequest("DELETE", url, nil)
-	if err != nil {
-		return err
-	}
-
-	if c.Token != "" {
-		req.Header.Set("accessToken", c.Token)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("删除配置失败: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("删除配置失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
-	}
-
-	return nil
+	_, err := c.doRequest(http.MethodDelete, "/nacos/v1/cs/configs", params, "删除配置")
+	return err
 }
 
 func (c *Client) ListConfigs(pageNo, pageSize int) ([]Config, error) {
-	// 使用Nacos v1 API的配置查询接口，根据curl命令构建请求
+	// 使用Nacos v1 API的配置查询接口
 	params := url.Values{}
 	params.Set("dataId", "") // 空字符串表示查询所有
 	params.Set("group", "")  // 空字符串表示查询所有
@@ -253,49 +298,15 @@ func (c *Client) ListConfigs(pageNo, pageSize int) ([]Config, error) {
 	if c.Namespace != "" {
 		params.Set("tenant", c.Namespace)
 	}
-	if c.Token != "" {
-		params.Set("accessToken", c.Token)
-	}
 	if c.Username != "" {
 		params.Set("username", c.Username)
 	}
 
-	// 使用Nacos v1 API的正确端点
-	url := c.ServerURL + "/nacos/v1/cs/configs?" + params.Encode()
-
-	// 使用Nacos v1 API的正确端点
-
-	// 使用GET方法调用Nacos v1配置查询API
-	req, err := http.NewRequest("GET", url, nil)
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/cs/configs", params, "获取配置列表")
 	if err != nil {
 		return nil, err
 	}
 
-	// 设置请求头
-	req.Header.Set("User-Agent", "nacos-cli")
-	req.Header.Set("Accept", "application/json")
-	if c.Token != "" {
-		req.Header.Set("accessToken", c.Token)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("获取配置列表失败: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	// 检查HTTP状态码
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("获取配置列表失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
-	}
-
-	// 解析响应
-
 	// Nacos v1 API响应格式
 	var result struct {
 		TotalCount     int      `json:"totalCount"`
@@ -314,36 +325,11 @@ func (c *Client) ListConfigs(pageNo, pageSize int) ([]Config, error) {
 // ListNamespaces 获取命名空间列表
 func (c *Client) ListNamespaces() ([]Namespace, error) {
 	// 使用Nacos v1 API
-	url := c.ServerURL + "/nacos/v1/console/namespaces"
-
-	req, err := http.NewRequest("GET", url, nil)
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/console/namespaces", nil, "获取命名空间列表")
 	if err != nil {
 		return nil, err
 	}
 
-	// 设置请求头
-	req.Header.Set("User-Agent", "nacos-cli")
-	req.Header.Set("Accept", "application/json")
-	if c.Token != "" {
-		req.Header.Set("accessToken", c.Token)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("获取命名空间列表失败: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	// 检查HTTP状态码
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("获取命名空间列表失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
-	}
-
 	var result struct {
 		Code    int         `json:"code"`
 		Message string      `json:"message"`
@@ -369,35 +355,8 @@ func (c *Client) CreateNamespace(namespaceId, namespaceName, namespaceDesc strin
 	data.Set("namespaceDesc", namespaceDesc)
 
 	// 使用Nacos v1 API
-	url := c.ServerURL + "/nacos/v1/console/namespaces"
-
-	req, err := http.NewRequest("POST", url, strings.NewReader(data.Encode()))
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("User-Agent", "nacos-cli")
-	if c.Token != "" {
-		req.Header.Set("accessToken", c.Token)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("创建命名空间失败: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("创建命名空间失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
-	}
-
-	return nil
+	_, err := c.doRequest(http.MethodPost, "/nacos/v1/console/namespaces", data, "创建命名空间")
+	return err
 }
 
 // DeleteNamespace 删除命名空间
@@ -406,32 +365,80 @@ func (c *Client) DeleteNamespace(namespaceId string) error {
 	params.Set("namespaceId", namespaceId)
 
 	// 使用Nacos v1 API
-	url := c.ServerURL + "/nacos/v1/console/namespaces?" + params.Encode()
+	_, err := c.doRequest(http.MethodDelete, "/nacos/v1/console/namespaces", params, "删除命名空间")
+	return err
+}
 
-	req, err := http.NewRequest("DELETE", url, nil)
+// doRequest 附加鉴权信息后发送请求并返回响应体。GET/DELETE 请求的参数放在查询串中，
+// 其他方法以表单形式提交；非200状态码返回 *APIError，action 用于组装错误信息
+func (c *Client) doRequest(method, path string, params url.Values, action string) ([]byte, error) {
+	return c.doRequestWithHeader(method, path, params, http.Header{}, action)
+}
+
+// doRequestWithHeader 与 doRequest 相同，并附加额外的请求头
+func (c *Client) doRequestWithHeader(method, path string, params url.Values, header http.Header, action string) ([]byte, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	if err := c.authenticator().Apply(c, path, params, header); err != nil {
+		return nil, err
+	}
+	return c.send(method, path, params, header, action)
+}
+
+// 发送请求，不附加鉴权信息
+func (c *Client) send(method, path string, params url.Values, header http.Header, action string) ([]byte, error) {
+	reqURL := c.ServerURL + path
+	var bodyReader io.Reader
+	if method == http.MethodGet || method == http.MethodDelete {
+		if encoded := params.Encode(); encoded != "" {
+			reqURL += "?" + encoded
+		}
+		c.debugf("%s %s", method, RedactURL(reqURL))
+	} else {
+		bodyReader = strings.NewReader(params.Encode())
+		c.debugf("%s %s, 参数: %s", method, reqURL, redactValues(params))
+	}
+
+	req, err := http.NewRequest(method, reqURL, bodyReader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// 设置请求头
+	for name, values := range c.Headers {
+		req.Header[name] = values
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if bodyReader != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 	req.Header.Set("User-Agent", "nacos-cli")
-	if c.Token != "" {
-		req.Header.Set("accessToken", c.Token)
+	req.Header.Set("Accept", "application/json")
+	if c.Debug {
+		c.debugf("请求头: %s", redactHeader(req.Header))
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("删除命名空间失败: %w", err)
+		return nil, fmt.Errorf("%s失败: %w", action, redactError(err))
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("读取响应失败: %w", err)
 	}
 
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("删除命名空间失败，状态码: %d, 响应: %s", resp.StatusCode, string(body))
+		return nil, &APIError{Action: action, StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	return nil
+	return body, nil
 }
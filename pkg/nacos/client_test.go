@@ -0,0 +1,76 @@
+package nacos
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublishConfigCas(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		conflict bool
+		wantErr  bool
+	}{
+		{"published", http.StatusOK, "true", false, false},
+		{"rejected", http.StatusOK, "false", true, true},
+		{"conflict status", http.StatusConflict, "conflict", true, true},
+		{"conflict message", http.StatusInternalServerError, `{"code":500,"message":"Cas publish fail, server md5 may have changed."}`, true, true},
+		// 其他包含 cas 字样的错误（如 because）不是冲突
+		{"other error", http.StatusInternalServerError, "publish failed because the database is read-only", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotHeader, gotForm string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotHeader = r.Header.Get("casMd5")
+				r.ParseForm()
+				gotForm = r.PostForm.Get("casMd5")
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "", "", "")
+			client.Auth = &NoAuth{}
+			err := client.PublishConfigCas(&Config{DataID: "app.yaml", Group: "DEFAULT_GROUP", Content: "a: 1"}, "abc123")
+
+			if gotHeader != "abc123" {
+				t.Errorf("casMd5 header = %q, want %q", gotHeader, "abc123")
+			}
+			if gotForm != "" {
+				t.Errorf("casMd5 form field = %q, want none", gotForm)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PublishConfigCas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrConfigConflict) != tt.conflict {
+				t.Errorf("PublishConfigCas() error = %v, conflict %v", err, tt.conflict)
+			}
+		})
+	}
+}
+
+func TestPublishConfigWithoutCas(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Header["Casmd5"]; ok {
+			t.Errorf("unexpected casMd5 header %q", r.Header.Get("casMd5"))
+		}
+		w.WriteHeader(http.StatusConflict)
+		io.WriteString(w, "conflict")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "", "", "")
+	client.Auth = &NoAuth{}
+	err := client.PublishConfig(&Config{DataID: "app.yaml", Group: "DEFAULT_GROUP", Content: "a: 1"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || errors.Is(err, ErrConfigConflict) {
+		t.Errorf("PublishConfig() error = %v, want *APIError", err)
+	}
+}
@@ -0,0 +1,107 @@
+package nacos
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// 调试日志中需要隐藏的参数名
+var secretParams = []string{"accessToken", "password", "newPassword", "signature"}
+
+// 输出调试日志到标准错误，Debug 未开启时不输出
+func (c *Client) debugf(format string, args ...interface{}) {
+	if !c.Debug {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "[debug] "+format+"\n", args...)
+}
+
+// RedactURL 隐藏 URL 查询参数中的令牌和密码
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	changed := false
+	for name := range query {
+		if isSecretParam(name) {
+			query.Set(name, "REDACTED")
+			changed = true
+		}
+	}
+	if !changed {
+		return rawURL
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// 隐藏网络错误中携带的请求 URL 里的敏感参数
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = RedactURL(urlErr.URL)
+	}
+	return err
+}
+
+// 返回隐藏了敏感参数的表单内容，用于调试日志
+func redactValues(params url.Values) string {
+	redacted := url.Values{}
+	for name, values := range params {
+		if isSecretParam(name) {
+			redacted.Set(name, "REDACTED")
+			continue
+		}
+		// 配置内容可能很长且包含敏感信息，只输出长度
+		if name == "content" {
+			redacted.Set(name, fmt.Sprintf("<%d字节>", len(params.Get(name))))
+			continue
+		}
+		redacted[name] = values
+	}
+	return redacted.Encode()
+}
+
+// 调试日志中可以原样输出的请求头，其余请求头（令牌、签名、身份标识等）的值都会被隐藏
+var plainHeaders = map[string]bool{
+	"Content-Type": true,
+	"User-Agent":   true,
+	"Accept":       true,
+	"Timestamp":    true,
+}
+
+// 返回隐藏了敏感值的请求头，按名称排序
+func redactHeader(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := "REDACTED"
+		if plainHeaders[http.CanonicalHeaderKey(name)] {
+			value = strings.Join(header[name], ",")
+		}
+		parts = append(parts, name+": "+value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// 判断参数名是否为敏感参数，不区分大小写
+func isSecretParam(name string) bool {
+	for _, secret := range secretParams {
+		if strings.EqualFold(secret, name) {
+			return true
+		}
+	}
+	return false
+}
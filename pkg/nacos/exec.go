@@ -0,0 +1,127 @@
+package nacos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// ExecAPIVersion 外部凭据插件协议的版本
+const ExecAPIVersion = "nacos-cli/v1"
+
+// ExecRequest 通过标准输入传给凭据插件的请求，同时以 JSON 形式放在 NACOS_CLI_EXEC_INFO 环境变量中
+type ExecRequest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Server     string `json:"server"`
+	Username   string `json:"username,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// ExecResponse 凭据插件输出到标准输出的响应。ExpiresAt（RFC3339）和 TokenTtl（秒）二选一，
+// 都未提供时令牌只在本次运行中使用，不会被缓存
+type ExecResponse struct {
+	APIVersion  string `json:"apiVersion"`
+	Kind        string `json:"kind"`
+	AccessToken string `json:"accessToken"`
+	ExpiresAt   string `json:"expiresAt,omitempty"`
+	TokenTtl    int    `json:"tokenTtl,omitempty"`
+	GlobalAdmin bool   `json:"globalAdmin,omitempty"`
+}
+
+// ExecAuth 运行外部命令获取访问令牌，用于对接企业内部的 SSO 等令牌签发服务。
+// 插件的标准错误直接输出到终端，便于插件提示用户完成交互式登录
+type ExecAuth struct {
+	Command string
+	Args    []string
+	Env     []string      // 追加的环境变量，格式 KEY=VALUE
+	Timeout time.Duration // 为0时默认60秒
+}
+
+func (a *ExecAuth) Login(c *Client) (*LoginResponse, error) {
+	if a.Command == "" {
+		return nil, fmt.Errorf("未设置凭据插件命令 auth.exec.command")
+	}
+
+	request, err := json.Marshal(ExecRequest{
+		APIVersion: ExecAPIVersion,
+		Kind:       "TokenRequest",
+		Server:     c.ServerURL,
+		Username:   c.Username,
+		Namespace:  c.Namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := a.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, a.Command, a.Args...)
+	cmd.Env = append(os.Environ(), "NACOS_CLI_EXEC_INFO="+string(request))
+	cmd.Env = append(cmd.Env, a.Env...)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stderr = os.Stderr
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	c.debugf("运行凭据插件: %s", a.Command)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("凭据插件 %s 超时（%s）", a.Command, timeout)
+		}
+		return nil, fmt.Errorf("运行凭据插件 %s 失败: %w", a.Command, err)
+	}
+
+	var resp ExecResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		// 输出中可能包含令牌，不在错误信息中回显
+		return nil, fmt.Errorf("解析凭据插件输出失败: %w", err)
+	}
+	if resp.APIVersion != "" && resp.APIVersion != ExecAPIVersion {
+		return nil, fmt.Errorf("凭据插件返回了不支持的协议版本: %s", resp.APIVersion)
+	}
+	if resp.AccessToken == "" {
+		return nil, fmt.Errorf("凭据插件没有返回 accessToken")
+	}
+
+	var expiry time.Time
+	switch {
+	case resp.ExpiresAt != "":
+		expiry, err = time.Parse(time.RFC3339, resp.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("凭据插件返回的 expiresAt 格式不正确: %w", err)
+		}
+	case resp.TokenTtl > 0:
+		expiry = time.Now().Add(time.Duration(resp.TokenTtl) * time.Second)
+	}
+
+	c.Token = resp.AccessToken
+	c.TokenExpiry = 0
+	ttl := 0
+	if !expiry.IsZero() {
+		c.TokenExpiry = expiry.Unix()
+		ttl = int(time.Until(expiry).Seconds())
+	}
+
+	return &LoginResponse{
+		AccessToken: resp.AccessToken,
+		TokenTtl:    ttl,
+		GlobalAdmin: resp.GlobalAdmin,
+	}, nil
+}
+
+// Apply 与用户名密码登录相同，请求时携带 accessToken
+func (a *ExecAuth) Apply(c *Client, path string, params url.Values, header http.Header) error {
+	return (&PasswordAuth{}).Apply(c, path, params, header)
+}
@@ -0,0 +1,141 @@
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Instance 服务实例
+type Instance struct {
+	InstanceID  string            `json:"instanceId"`
+	IP          string            `json:"ip"`
+	Port        int               `json:"port"`
+	Weight      float64           `json:"weight"`
+	Healthy     bool              `json:"healthy"`
+	Enabled     bool              `json:"enabled"`
+	Ephemeral   bool              `json:"ephemeral"`
+	ClusterName string            `json:"clusterName"`
+	ServiceName string            `json:"serviceName"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+// Address 返回 host:port 形式的地址
+func (i Instance) Address() string {
+	return net.JoinHostPort(i.IP, strconv.Itoa(i.Port))
+}
+
+// URL 返回实例的访问地址，scheme 为空时使用 http
+func (i Instance) URL(scheme string) string {
+	if scheme == "" {
+		scheme = "http"
+	}
+	return scheme + "://" + i.Address()
+}
+
+// ListInstances 获取服务的实例列表，clusters 为空表示所有集群
+func (c *Client) ListInstances(serviceName, groupName string, clusters []string, healthyOnly bool) ([]Instance, error) {
+	params := url.Values{}
+	params.Set("serviceName", serviceName)
+	if groupName != "" {
+		params.Set("groupName", groupName)
+	}
+	if len(clusters) > 0 {
+		params.Set("clusters", strings.Join(clusters, ","))
+	}
+	if healthyOnly {
+		params.Set("healthyOnly", "true")
+	}
+	if c.Namespace != "" {
+		params.Set("namespaceId", c.Namespace)
+	}
+
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/ns/instance/list", params, "获取实例列表")
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Name  string     `json:"name"`
+		Hosts []Instance `json:"hosts"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析实例列表失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	return result.Hosts, nil
+}
+
+// UpdateInstanceMetadata 批量更新实例元数据，已有的键会被覆盖
+func (c *Client) UpdateInstanceMetadata(serviceName, groupName string, instances []Instance, metadata map[string]string) error {
+	return c.batchInstanceMetadata(http.MethodPut, serviceName, groupName, instances, metadata, "更新实例元数据")
+}
+
+// RemoveInstanceMetadata 批量删除实例元数据中的指定键
+func (c *Client) RemoveInstanceMetadata(serviceName, groupName string, instances []Instance, keys []string) error {
+	metadata := make(map[string]string, len(keys))
+	for _, key := range keys {
+		metadata[key] = ""
+	}
+	return c.batchInstanceMetadata(http.MethodDelete, serviceName, groupName, instances, metadata, "删除实例元数据")
+}
+
+// 调用批量元数据接口，临时实例和持久实例需要分别提交
+func (c *Client) batchInstanceMetadata(method, serviceName, groupName string, instances []Instance, metadata map[string]string, action string) error {
+	type batchInstance struct {
+		IP          string `json:"ip"`
+		Port        int    `json:"port"`
+		Ephemeral   string `json:"ephemeral"`
+		ClusterName string `json:"clusterName"`
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	byType := map[bool][]batchInstance{}
+	for _, inst := range instances {
+		byType[inst.Ephemeral] = append(byType[inst.Ephemeral], batchInstance{
+			IP:          inst.IP,
+			Port:        inst.Port,
+			Ephemeral:   strconv.FormatBool(inst.Ephemeral),
+			ClusterName: inst.ClusterName,
+		})
+	}
+
+	for ephemeral, batch := range byType {
+		instancesJSON, err := json.Marshal(batch)
+		if err != nil {
+			return err
+		}
+
+		params := url.Values{}
+		params.Set("serviceName", serviceName)
+		if groupName != "" {
+			params.Set("groupName", groupName)
+		}
+		if c.Namespace != "" {
+			params.Set("namespaceId", c.Namespace)
+		}
+		if ephemeral {
+			params.Set("consistencyType", "ephemeral")
+		} else {
+			params.Set("consistencyType", "persist")
+		}
+		params.Set("instances", string(instancesJSON))
+		params.Set("metadata", string(metadataJSON))
+
+		// 使用Nacos v1 API
+		if _, err := c.doRequest(method, "/nacos/v1/ns/instance/metadata/batch", params, action); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// NamingMetrics 命名模块的运行指标
+type NamingMetrics struct {
+	Status                   string  `json:"status"`
+	ServiceCount             int     `json:"serviceCount"`
+	InstanceCount            int     `json:"instanceCount"`
+	SubscribeCount           int     `json:"subscribeCount"`
+	ResponsibleServiceCount  int     `json:"responsibleServiceCount"`
+	ResponsibleInstanceCount int     `json:"responsibleInstanceCount"`
+	ClientCount              int     `json:"clientCount"`
+	ResponsibleClientCount   int     `json:"responsibleClientCount"`
+	RaftNotifyTaskCount      int     `json:"raftNotifyTaskCount"`
+	CPU                      float64 `json:"cpu"`
+	Load                     float64 `json:"load"`
+	Mem                      float64 `json:"mem"`
+}
+
+// GetSwitches 获取命名模块的开关配置
+func (c *Client) GetSwitches() (map[string]interface{}, error) {
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/ns/operator/switches", nil, "获取开关配置")
+	if err != nil {
+		return nil, err
+	}
+
+	var switches map[string]interface{}
+	if err := json.Unmarshal(body, &switches); err != nil {
+		return nil, fmt.Errorf("解析开关配置失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	return switches, nil
+}
+
+// UpdateSwitch 修改命名模块的开关，debug 为 true 时只修改当前节点
+func (c *Client) UpdateSwitch(entry, value string, debug bool) error {
+	params := url.Values{}
+	params.Set("entry", entry)
+	params.Set("value", value)
+	params.Set("debug", strconv.FormatBool(debug))
+
+	// 使用Nacos v1 API
+	_, err := c.doRequest(http.MethodPut, "/nacos/v1/ns/operator/switches", params, "修改开关配置")
+	return err
+}
+
+// GetNamingMetrics 获取命名模块的服务数、实例数等指标
+func (c *Client) GetNamingMetrics() (*NamingMetrics, error) {
+	params := url.Values{}
+	params.Set("onlyStatus", "false")
+
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/ns/operator/metrics", params, "获取命名模块指标")
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics NamingMetrics
+	if err := json.Unmarshal(body, &metrics); err != nil {
+		return nil, fmt.Errorf("解析命名模块指标失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	return &metrics, nil
+}
@@ -0,0 +1,250 @@
+package nacos
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RBACSpec 声明式的用户、角色绑定和权限描述
+type RBACSpec struct {
+	Users []RBACUser `yaml:"users" json:"users"`
+	Roles []RBACRole `yaml:"roles" json:"roles"`
+}
+
+// RBACUser 声明的用户，PasswordEnv 指定创建用户时读取密码的环境变量
+type RBACUser struct {
+	Username    string `yaml:"username" json:"username"`
+	PasswordEnv string `yaml:"passwordEnv,omitempty" json:"passwordEnv,omitempty"`
+}
+
+// RBACRole 声明的角色及其成员和权限
+type RBACRole struct {
+	Role        string           `yaml:"role" json:"role"`
+	Users       []string         `yaml:"users" json:"users"`
+	Permissions []RBACPermission `yaml:"permissions" json:"permissions"`
+}
+
+// RBACPermission 声明的权限，Resource 格式同 NormalizeResource
+type RBACPermission struct {
+	Resource string `yaml:"resource" json:"resource"`
+	Action   string `yaml:"action" json:"action"`
+}
+
+// RBACState 服务端当前的用户、角色绑定和权限
+type RBACState struct {
+	Users       []string
+	Bindings    []RoleBinding
+	Permissions []Permission
+}
+
+// RBAC 变更动作类型
+const (
+	ActionCreateUser = "create-user"
+	ActionDeleteUser = "delete-user"
+	ActionBindRole   = "bind"
+	ActionUnbindRole = "unbind"
+	ActionGrant      = "grant"
+	ActionRevoke     = "revoke"
+)
+
+// RBACAction 使服务端收敛到声明状态所需的一个变更
+type RBACAction struct {
+	Type     string `json:"type"`
+	Username string `json:"username,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Resource string `json:"resource,omitempty"`
+	Action   string `json:"action,omitempty"`
+}
+
+func (a RBACAction) String() string {
+	switch a.Type {
+	case ActionCreateUser:
+		return fmt.Sprintf("+ 创建用户 %s", a.Username)
+	case ActionDeleteUser:
+		return fmt.Sprintf("- 删除用户 %s", a.Username)
+	case ActionBindRole:
+		return fmt.Sprintf("+ 绑定角色 %s -> %s", a.Username, a.Role)
+	case ActionUnbindRole:
+		return fmt.Sprintf("- 解绑角色 %s -> %s", a.Username, a.Role)
+	case ActionGrant:
+		return fmt.Sprintf("+ 授予权限 %s %s %s", a.Role, a.Resource, a.Action)
+	case ActionRevoke:
+		return fmt.Sprintf("- 收回权限 %s %s %s", a.Role, a.Resource, a.Action)
+	default:
+		return a.Type
+	}
+}
+
+// Normalize 校验声明并补全资源格式
+func (s *RBACSpec) Normalize() error {
+	users := make(map[string]bool)
+	for _, u := range s.Users {
+		if u.Username == "" {
+			return fmt.Errorf("用户名不能为空")
+		}
+		if users[u.Username] {
+			return fmt.Errorf("用户 %s 重复声明", u.Username)
+		}
+		users[u.Username] = true
+	}
+
+	roles := make(map[string]bool)
+	for i := range s.Roles {
+		role := &s.Roles[i]
+		if role.Role == "" {
+			return fmt.Errorf("角色名不能为空")
+		}
+		if roles[role.Role] {
+			return fmt.Errorf("角色 %s 重复声明", role.Role)
+		}
+		roles[role.Role] = true
+
+		for j := range role.Permissions {
+			p := &role.Permissions[j]
+			resource, err := NormalizeResource(p.Resource)
+			if err != nil {
+				return fmt.Errorf("角色 %s: %w", role.Role, err)
+			}
+			if err := ValidateAction(p.Action); err != nil {
+				return fmt.Errorf("角色 %s: %w", role.Role, err)
+			}
+			p.Resource = resource
+		}
+	}
+	return nil
+}
+
+// PlanRBAC 比较声明和服务端状态，返回需要执行的变更。
+// prune 为 false 时只新增，不删除声明之外的用户、绑定和权限
+func PlanRBAC(spec *RBACSpec, state *RBACState, prune bool) []RBACAction {
+	var creates, binds, grants, revokes, unbinds, deletes []RBACAction
+
+	existingUsers := make(map[string]bool)
+	for _, u := range state.Users {
+		existingUsers[u] = true
+	}
+	declaredUsers := make(map[string]bool)
+	for _, u := range spec.Users {
+		declaredUsers[u.Username] = true
+		if !existingUsers[u.Username] {
+			creates = append(creates, RBACAction{Type: ActionCreateUser, Username: u.Username})
+		}
+	}
+
+	existingBindings := make(map[RoleBinding]bool)
+	for _, b := range state.Bindings {
+		existingBindings[b] = true
+	}
+	existingPermissions := make(map[Permission]bool)
+	for _, p := range state.Permissions {
+		existingPermissions[p] = true
+	}
+
+	declaredBindings := make(map[RoleBinding]bool)
+	declaredPermissions := make(map[Permission]bool)
+	for _, role := range spec.Roles {
+		for _, username := range role.Users {
+			b := RoleBinding{Role: role.Role, Username: username}
+			declaredBindings[b] = true
+			if !existingBindings[b] {
+				binds = append(binds, RBACAction{Type: ActionBindRole, Role: b.Role, Username: b.Username})
+			}
+		}
+		for _, rp := range role.Permissions {
+			p := Permission{Role: role.Role, Resource: rp.Resource, Action: rp.Action}
+			declaredPermissions[p] = true
+			if !existingPermissions[p] {
+				grants = append(grants, RBACAction{Type: ActionGrant, Role: p.Role, Resource: p.Resource, Action: p.Action})
+			}
+		}
+	}
+
+	if prune {
+		for _, p := range state.Permissions {
+			if !declaredPermissions[p] {
+				revokes = append(revokes, RBACAction{Type: ActionRevoke, Role: p.Role, Resource: p.Resource, Action: p.Action})
+			}
+		}
+		for _, b := range state.Bindings {
+			if !declaredBindings[b] {
+				unbinds = append(unbinds, RBACAction{Type: ActionUnbindRole, Role: b.Role, Username: b.Username})
+			}
+		}
+		for _, u := range state.Users {
+			if !declaredUsers[u] {
+				deletes = append(deletes, RBACAction{Type: ActionDeleteUser, Username: u})
+			}
+		}
+	}
+
+	// 先新增后删除，避免收敛过程中出现短暂的权限缺失
+	var actions []RBACAction
+	for _, group := range [][]RBACAction{creates, binds, grants, revokes, unbinds, deletes} {
+		sort.SliceStable(group, func(i, j int) bool { return group[i].String() < group[j].String() })
+		actions = append(actions, group...)
+	}
+	return actions
+}
+
+// AdminRemovals 返回变更中解除 ROLE_ADMIN 绑定、或删除持有 ROLE_ADMIN 角色的用户的项。
+// 使用 AK/SK 等不带用户名的鉴权方式时无法识别当前用户，需要据此拒绝误删管理员
+func AdminRemovals(state *RBACState, actions []RBACAction) []RBACAction {
+	admins := make(map[string]bool)
+	for _, b := range state.Bindings {
+		if b.Role == AdminRole {
+			admins[b.Username] = true
+		}
+	}
+	var removals []RBACAction
+	for _, action := range actions {
+		switch {
+		case action.Type == ActionUnbindRole && action.Role == AdminRole,
+			action.Type == ActionDeleteUser && admins[action.Username]:
+			removals = append(removals, action)
+		}
+	}
+	return removals
+}
+
+// FetchRBACState 分页读取服务端所有的用户、角色绑定和权限
+func (c *Client) FetchRBACState() (*RBACState, error) {
+	const pageSize = 100
+	state := &RBACState{}
+
+	for pageNo := 1; ; pageNo++ {
+		page, err := c.ListUsers(pageNo, pageSize, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range page.PageItems {
+			state.Users = append(state.Users, u.Username)
+		}
+		if len(page.PageItems) < pageSize {
+			break
+		}
+	}
+
+	for pageNo := 1; ; pageNo++ {
+		page, err := c.ListRoles(pageNo, pageSize, "", "")
+		if err != nil {
+			return nil, err
+		}
+		state.Bindings = append(state.Bindings, page.PageItems...)
+		if len(page.PageItems) < pageSize {
+			break
+		}
+	}
+
+	for pageNo := 1; ; pageNo++ {
+		page, err := c.ListPermissions(pageNo, pageSize, "")
+		if err != nil {
+			return nil, err
+		}
+		state.Permissions = append(state.Permissions, page.PageItems...)
+		if len(page.PageItems) < pageSize {
+			break
+		}
+	}
+
+	return state, nil
+}
@@ -0,0 +1,39 @@
+package nacos
+
+import "testing"
+
+func TestAdminRemovals(t *testing.T) {
+	state := &RBACState{
+		Users: []string{"nacos", "alice", "bob"},
+		Bindings: []RoleBinding{
+			{Role: AdminRole, Username: "nacos"},
+			{Role: "ROLE_DEV", Username: "alice"},
+			{Role: "ROLE_DEV", Username: "bob"},
+		},
+	}
+	// 声明中只保留 alice，prune 会删除 nacos 和 bob
+	spec := &RBACSpec{
+		Users: []RBACUser{{Username: "alice"}},
+		Roles: []RBACRole{{Role: "ROLE_DEV", Users: []string{"alice"}}},
+	}
+
+	actions := PlanRBAC(spec, state, true)
+	removals := AdminRemovals(state, actions)
+
+	want := map[string]bool{
+		RBACAction{Type: ActionUnbindRole, Role: AdminRole, Username: "nacos"}.String(): true,
+		RBACAction{Type: ActionDeleteUser, Username: "nacos"}.String():                  true,
+	}
+	if len(removals) != len(want) {
+		t.Fatalf("AdminRemovals() = %v, want %d items", removals, len(want))
+	}
+	for _, action := range removals {
+		if !want[action.String()] {
+			t.Errorf("unexpected removal %s", action)
+		}
+	}
+
+	if removals := AdminRemovals(state, PlanRBAC(spec, state, false)); len(removals) != 0 {
+		t.Errorf("AdminRemovals() without prune = %v, want none", removals)
+	}
+}
@@ -0,0 +1,179 @@
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// 内置的管理员角色
+const AdminRole = "ROLE_ADMIN"
+
+// RoleBinding 用户与角色的绑定关系
+type RoleBinding struct {
+	Role     string `json:"role"`
+	Username string `json:"username"`
+}
+
+// RolePage 角色分页查询结果
+type RolePage struct {
+	TotalCount     int           `json:"totalCount"`
+	PageNumber     int           `json:"pageNumber"`
+	PagesAvailable int           `json:"pagesAvailable"`
+	PageItems      []RoleBinding `json:"pageItems"`
+}
+
+// Permission 角色对资源的权限，Resource 格式为 namespace:group:dataId
+type Permission struct {
+	Role     string `json:"role"`
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+}
+
+// PermissionPage 权限分页查询结果
+type PermissionPage struct {
+	TotalCount     int          `json:"totalCount"`
+	PageNumber     int          `json:"pageNumber"`
+	PagesAvailable int          `json:"pagesAvailable"`
+	PageItems      []Permission `json:"pageItems"`
+}
+
+// NormalizeResource 将 namespace[:group[:dataId]] 补全为 namespace:group:dataId，缺省部分用 * 表示
+func NormalizeResource(resource string) (string, error) {
+	parts := strings.Split(resource, ":")
+	if len(parts) > 3 {
+		return "", fmt.Errorf("资源格式不正确，应为 namespace[:group[:dataId]]: %s", resource)
+	}
+	for len(parts) < 3 {
+		parts = append(parts, "*")
+	}
+	for i := 1; i < 3; i++ {
+		if parts[i] == "" {
+			parts[i] = "*"
+		}
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+// ValidateAction 校验权限动作，只允许 r、w、rw
+func ValidateAction(action string) error {
+	switch action {
+	case "r", "w", "rw":
+		return nil
+	default:
+		return fmt.Errorf("不支持的权限动作: %s，可选值: r, w, rw", action)
+	}
+}
+
+// ListRoles 分页查询角色绑定，username 和 role 为空表示不过滤
+func (c *Client) ListRoles(pageNo, pageSize int, username, role string) (*RolePage, error) {
+	params := url.Values{}
+	params.Set("pageNo", strconv.Itoa(pageNo))
+	params.Set("pageSize", strconv.Itoa(pageSize))
+	params.Set("username", username)
+	params.Set("role", role)
+	params.Set("search", "accurate")
+
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/auth/roles", params, "获取角色列表")
+	if err != nil {
+		return nil, err
+	}
+
+	var page RolePage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("解析角色列表失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	return &page, nil
+}
+
+// SearchRoles 按角色名前缀搜索，返回匹配的角色名
+func (c *Client) SearchRoles(role string) ([]string, error) {
+	params := url.Values{}
+	params.Set("role", role)
+
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/auth/roles/search", params, "搜索角色")
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []string
+	if err := json.Unmarshal(body, &roles); err != nil {
+		return nil, fmt.Errorf("解析角色搜索结果失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	return roles, nil
+}
+
+// BindRole 将用户绑定到角色，角色不存在时由服务端创建
+func (c *Client) BindRole(role, username string) error {
+	params := url.Values{}
+	params.Set("role", role)
+	params.Set("username", username)
+
+	// 使用Nacos v1 API
+	_, err := c.doRequest(http.MethodPost, "/nacos/v1/auth/roles", params, "绑定角色")
+	return err
+}
+
+// UnbindRole 解除用户与角色的绑定
+func (c *Client) UnbindRole(role, username string) error {
+	params := url.Values{}
+	params.Set("role", role)
+	params.Set("username", username)
+
+	// 使用Nacos v1 API
+	_, err := c.doRequest(http.MethodDelete, "/nacos/v1/auth/roles", params, "解绑角色")
+	return err
+}
+
+// ListPermissions 分页查询权限，role 为空表示所有角色
+func (c *Client) ListPermissions(pageNo, pageSize int, role string) (*PermissionPage, error) {
+	params := url.Values{}
+	params.Set("pageNo", strconv.Itoa(pageNo))
+	params.Set("pageSize", strconv.Itoa(pageSize))
+	params.Set("role", role)
+	params.Set("search", "accurate")
+
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/auth/permissions", params, "获取权限列表")
+	if err != nil {
+		return nil, err
+	}
+
+	var page PermissionPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("解析权限列表失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	return &page, nil
+}
+
+// GrantPermission 为角色授予资源权限
+func (c *Client) GrantPermission(role, resource, action string) error {
+	params := url.Values{}
+	params.Set("role", role)
+	params.Set("resource", resource)
+	params.Set("action", action)
+
+	// 使用Nacos v1 API
+	_, err := c.doRequest(http.MethodPost, "/nacos/v1/auth/permissions", params, "授予权限")
+	return err
+}
+
+// RevokePermission 收回角色的资源权限
+func (c *Client) RevokePermission(role, resource, action string) error {
+	params := url.Values{}
+	params.Set("role", role)
+	params.Set("resource", resource)
+	params.Set("action", action)
+
+	// 使用Nacos v1 API
+	_, err := c.doRequest(http.MethodDelete, "/nacos/v1/auth/permissions", params, "收回权限")
+	return err
+}
@@ -0,0 +1,241 @@
+package nacos
+
+import (
+	"errors"
+	"math/rand"
+	"path"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoInstance 没有可供选择的实例
+var ErrNoInstance = errors.New("没有可用的实例")
+
+// Selector 从实例列表中选出一个实例
+type Selector interface {
+	Select(instances []Instance) (*Instance, error)
+}
+
+// FilterAvailable 过滤出健康、已启用且权重大于0的实例
+func FilterAvailable(instances []Instance) []Instance {
+	var result []Instance
+	for _, inst := range instances {
+		if inst.Healthy && inst.Enabled && inst.Weight > 0 {
+			result = append(result, inst)
+		}
+	}
+	return result
+}
+
+// MatchInstance 判断实例是否满足所有条件。ip、port、cluster 匹配实例本身的属性，
+// 其余键匹配元数据；值支持 path.Match 风格的通配符，如 10.0.*
+func MatchInstance(inst Instance, match map[string]string) bool {
+	for key, pattern := range match {
+		var value string
+		var ok bool
+		switch key {
+		case "ip":
+			value, ok = inst.IP, true
+		case "port":
+			value, ok = strconv.Itoa(inst.Port), true
+		case "cluster":
+			value, ok = inst.ClusterName, true
+		default:
+			value, ok = inst.Metadata[key]
+		}
+		if !ok {
+			return false
+		}
+		matched, err := path.Match(pattern, value)
+		if err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterInstances 返回满足所有条件的实例
+func FilterInstances(instances []Instance, match map[string]string) []Instance {
+	var result []Instance
+	for _, inst := range instances {
+		if MatchInstance(inst, match) {
+			result = append(result, inst)
+		}
+	}
+	return result
+}
+
+// PreferClusters 返回位于指定集群中的实例，这些集群中没有实例时返回全部实例
+func PreferClusters(instances []Instance, clusters []string) []Instance {
+	if len(clusters) == 0 {
+		return instances
+	}
+	var preferred []Instance
+	for _, inst := range instances {
+		for _, cluster := range clusters {
+			if inst.ClusterName == cluster {
+				preferred = append(preferred, inst)
+				break
+			}
+		}
+	}
+	if len(preferred) == 0 {
+		return instances
+	}
+	return preferred
+}
+
+// WeightedRandomSelector 按权重随机选择实例
+type WeightedRandomSelector struct {
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewWeightedRandomSelector 创建按权重随机选择的选择器
+func NewWeightedRandomSelector() *WeightedRandomSelector {
+	return &WeightedRandomSelector{rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (s *WeightedRandomSelector) Select(instances []Instance) (*Instance, error) {
+	if len(instances) == 0 {
+		return nil, ErrNoInstance
+	}
+
+	total := 0.0
+	for _, inst := range instances {
+		if inst.Weight > 0 {
+			total += inst.Weight
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 所有实例权重都为0时退化为均匀随机
+	if total <= 0 {
+		inst := instances[s.rnd.Intn(len(instances))]
+		return &inst, nil
+	}
+
+	point := s.rnd.Float64() * total
+	for _, inst := range instances {
+		if inst.Weight <= 0 {
+			continue
+		}
+		point -= inst.Weight
+		if point < 0 {
+			inst := inst
+			return &inst, nil
+		}
+	}
+	inst := instances[len(instances)-1]
+	return &inst, nil
+}
+
+// RoundRobinSelector 依次轮询实例，可在多个 goroutine 中共享
+type RoundRobinSelector struct {
+	next uint64
+}
+
+func (s *RoundRobinSelector) Select(instances []Instance) (*Instance, error) {
+	if len(instances) == 0 {
+		return nil, ErrNoInstance
+	}
+	n := atomic.AddUint64(&s.next, 1) - 1
+	inst := instances[n%uint64(len(instances))]
+	return &inst, nil
+}
+
+// ClusterPreferredSelector 优先在指定集群中选择，这些集群没有实例时回退到全部实例
+type ClusterPreferredSelector struct {
+	Clusters []string
+	Next     Selector
+}
+
+func (s *ClusterPreferredSelector) Select(instances []Instance) (*Instance, error) {
+	return s.Next.Select(PreferClusters(instances, s.Clusters))
+}
+
+// MetadataSelector 先按条件过滤实例，再交给 Next 选择，条件格式同 MatchInstance
+type MetadataSelector struct {
+	Match map[string]string
+	Next  Selector
+}
+
+func (s *MetadataSelector) Select(instances []Instance) (*Instance, error) {
+	return s.Next.Select(FilterInstances(instances, s.Match))
+}
+
+// Resolver 将服务名解析为实例，缓存实例列表以减少请求。
+// 监听到实例变更时可调用 Update 推送最新列表
+type Resolver struct {
+	Client      *Client
+	ServiceName string
+	GroupName   string
+	Clusters    []string
+	Selector    Selector
+	TTL         time.Duration // 缓存有效期，0表示每次都重新获取
+
+	mu        sync.Mutex
+	instances []Instance
+	fetchedAt time.Time
+}
+
+// NewResolver 创建解析器，selector 为空时使用按权重随机选择
+func NewResolver(client *Client, serviceName, groupName string, selector Selector) *Resolver {
+	if selector == nil {
+		selector = NewWeightedRandomSelector()
+	}
+	return &Resolver{
+		Client:      client,
+		ServiceName: serviceName,
+		GroupName:   groupName,
+		Selector:    selector,
+	}
+}
+
+// Refresh 从服务端重新获取实例列表
+func (r *Resolver) Refresh() error {
+	instances, err := r.Client.ListInstances(r.ServiceName, r.GroupName, r.Clusters, false)
+	if err != nil {
+		return err
+	}
+	r.Update(instances)
+	return nil
+}
+
+// Update 直接替换缓存的实例列表
+func (r *Resolver) Update(instances []Instance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instances = instances
+	r.fetchedAt = time.Now()
+}
+
+// Instances 返回当前可用的实例，缓存过期时自动刷新
+func (r *Resolver) Instances() ([]Instance, error) {
+	r.mu.Lock()
+	expired := r.fetchedAt.IsZero() || time.Since(r.fetchedAt) > r.TTL
+	r.mu.Unlock()
+
+	if expired {
+		if err := r.Refresh(); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return FilterAvailable(r.instances), nil
+}
+
+// Resolve 选出一个可用实例
+func (r *Resolver) Resolve() (*Instance, error) {
+	instances, err := r.Instances()
+	if err != nil {
+		return nil, err
+	}
+	return r.Selector.Select(instances)
+}
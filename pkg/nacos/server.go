@@ -0,0 +1,135 @@
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// ClusterMember 集群节点
+type ClusterMember struct {
+	IP            string                 `json:"ip"`
+	Port          int                    `json:"port"`
+	State         string                 `json:"state"`
+	Address       string                 `json:"address"`
+	FailAccessCnt int                    `json:"failAccessCnt"`
+	ExtendInfo    map[string]interface{} `json:"extendInfo"`
+	Abilities     map[string]interface{} `json:"abilities,omitempty"`
+}
+
+// RaftGroup 节点视角下的 Raft 分组信息
+type RaftGroup struct {
+	Group   string   `json:"group"`
+	Leader  string   `json:"leader"`
+	Term    int64    `json:"term"`
+	Members []string `json:"raftGroupMember"`
+}
+
+// Version 返回节点上报的服务端版本
+func (m ClusterMember) Version() string {
+	if v, ok := m.ExtendInfo["version"].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// RaftGroups 从扩展信息中解析 Raft 元数据，按分组名排序
+func (m ClusterMember) RaftGroups() []RaftGroup {
+	raw, ok := m.ExtendInfo["raftMetaData"]
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var meta struct {
+		MetaDataMap map[string]RaftGroup `json:"metaDataMap"`
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil
+	}
+
+	groups := make([]RaftGroup, 0, len(meta.MetaDataMap))
+	for name, group := range meta.MetaDataMap {
+		group.Group = name
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Group < groups[j].Group })
+	return groups
+}
+
+// ServerState 服务端状态
+type ServerState struct {
+	Version        string            `json:"version"`
+	StandaloneMode string            `json:"standaloneMode"`
+	FunctionMode   string            `json:"functionMode"`
+	AuthEnabled    bool              `json:"authEnabled"`
+	Raw            map[string]string `json:"raw"`
+}
+
+// ListClusterMembers 获取集群节点列表
+func (c *Client) ListClusterMembers() ([]ClusterMember, error) {
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/core/cluster/nodes", nil, "获取集群节点")
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    []ClusterMember `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("解析集群节点失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	if result.Code != 200 {
+		return nil, fmt.Errorf("API调用失败: %s", result.Message)
+	}
+
+	return result.Data, nil
+}
+
+// GetServerState 获取服务端版本、运行模式和鉴权开关
+func (c *Client) GetServerState() (*ServerState, error) {
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/console/server/state", nil, "获取服务端状态")
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("解析服务端状态失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	state := &ServerState{Raw: make(map[string]string, len(raw))}
+	for key, value := range raw {
+		if value == nil {
+			continue
+		}
+		state.Raw[key] = fmt.Sprint(value)
+	}
+	state.Version = state.Raw["version"]
+	state.StandaloneMode = state.Raw["standalone_mode"]
+	state.FunctionMode = state.Raw["function_mode"]
+	state.AuthEnabled = state.Raw["auth_enabled"] == "true"
+
+	return state, nil
+}
+
+// CheckLiveness 检查服务端存活状态，未存活时返回错误
+func (c *Client) CheckLiveness() error {
+	_, err := c.doRequest(http.MethodGet, "/nacos/v1/console/health/liveness", nil, "存活检查")
+	return err
+}
+
+// CheckReadiness 检查服务端就绪状态，未就绪时返回错误
+func (c *Client) CheckReadiness() error {
+	_, err := c.doRequest(http.MethodGet, "/nacos/v1/console/health/readiness", nil, "就绪检查")
+	return err
+}
@@ -0,0 +1,65 @@
+package nacos
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSConfig 连接 https 服务器时使用的TLS设置
+type TLSConfig struct {
+	CAFile             string // 额外信任的CA证书（PEM）
+	CertFile           string // 客户端证书，用于双向TLS
+	KeyFile            string // 客户端证书的私钥
+	ServerName         string // 校验服务端证书时使用的主机名，为空时使用服务器地址中的主机名
+	InsecureSkipVerify bool   // 不校验服务端证书，仅用于测试环境
+}
+
+// IsZero 是否没有任何TLS设置
+func (t TLSConfig) IsZero() bool {
+	return t == TLSConfig{}
+}
+
+// NewHTTPClient 按TLS设置创建HTTP客户端，没有任何设置时返回 http.DefaultClient
+func NewHTTPClient(t TLSConfig) (*http.Client, error) {
+	if t.IsZero() {
+		return http.DefaultClient, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取CA证书失败: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA证书 %s 中没有有效的PEM证书", t.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		if t.CertFile == "" || t.KeyFile == "" {
+			return nil, fmt.Errorf("客户端证书和私钥必须同时设置")
+		}
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: transport}, nil
+}
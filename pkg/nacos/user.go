@@ -0,0 +1,99 @@
+package nacos
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// User 服务端用户
+type User struct {
+	Username string `json:"username"`
+}
+
+// UserPage 用户分页查询结果
+type UserPage struct {
+	TotalCount     int    `json:"totalCount"`
+	PageNumber     int    `json:"pageNumber"`
+	PagesAvailable int    `json:"pagesAvailable"`
+	PageItems      []User `json:"pageItems"`
+}
+
+// ListUsers 分页查询用户，username 不为空时按用户名模糊搜索
+func (c *Client) ListUsers(pageNo, pageSize int, username string) (*UserPage, error) {
+	params := url.Values{}
+	params.Set("pageNo", strconv.Itoa(pageNo))
+	params.Set("pageSize", strconv.Itoa(pageSize))
+	if username != "" {
+		params.Set("username", username)
+		params.Set("search", "blur")
+	} else {
+		params.Set("search", "accurate")
+	}
+
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/auth/users", params, "获取用户列表")
+	if err != nil {
+		return nil, err
+	}
+
+	var page UserPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("解析用户列表失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	return &page, nil
+}
+
+// SearchUsers 按用户名前缀搜索，返回匹配的用户名
+func (c *Client) SearchUsers(username string) ([]string, error) {
+	params := url.Values{}
+	params.Set("username", username)
+
+	// 使用Nacos v1 API
+	body, err := c.doRequest(http.MethodGet, "/nacos/v1/auth/users/search", params, "搜索用户")
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if err := json.Unmarshal(body, &names); err != nil {
+		return nil, fmt.Errorf("解析用户搜索结果失败: %w, 原始响应: %s", err, string(body))
+	}
+
+	return names, nil
+}
+
+// CreateUser 创建用户
+func (c *Client) CreateUser(username, password string) error {
+	params := url.Values{}
+	params.Set("username", username)
+	params.Set("password", password)
+
+	// 使用Nacos v1 API
+	_, err := c.doRequest(http.MethodPost, "/nacos/v1/auth/users", params, "创建用户")
+	return err
+}
+
+// DeleteUser 删除用户
+func (c *Client) DeleteUser(username string) error {
+	params := url.Values{}
+	params.Set("username", username)
+
+	// 使用Nacos v1 API
+	_, err := c.doRequest(http.MethodDelete, "/nacos/v1/auth/users", params, "删除用户")
+	return err
+}
+
+// UpdateUserPassword 修改用户密码
+func (c *Client) UpdateUserPassword(username, newPassword string) error {
+	params := url.Values{}
+	params.Set("username", username)
+	params.Set("newPassword", newPassword)
+
+	// 使用Nacos v1 API
+	_, err := c.doRequest(http.MethodPut, "/nacos/v1/auth/users", params, "修改密码")
+	return err
+}
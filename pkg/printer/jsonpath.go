@@ -0,0 +1,608 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath kubectl 风格的 JSONPath 模板，如 '{.items[*].dataId}'、
+// '{range .items[*]}{.dataId}{"\t"}{.group}{"\n"}{end}'。
+// 支持字段、[n]、[start:end]、[*]、..递归查找、['name']、[?(@.x == "y")] 过滤和 range/end。
+// 与 kubectl 一致，字段在所有取值对象中都不存在或下标越界时返回错误，过滤条件中缺少的字段视为不匹配
+type JSONPath struct {
+	nodes []jsonPathNode
+}
+
+type jsonPathNode struct {
+	text     string         // 原样输出的文本
+	literal  bool           // text 来自模板中的字符串字面量或普通文本
+	path     []jsonPathStep // 取值表达式
+	fromRoot bool           // 以 $ 开头，始终从根对象取值
+	children []jsonPathNode // range 的内容
+	isRange  bool
+}
+
+type jsonPathStep struct {
+	kind   string // field, recursive, wildcard, index, slice, filter
+	name   string
+	index  int
+	start  *int
+	end    *int
+	filter *jsonPathFilter
+}
+
+type jsonPathFilter struct {
+	path  []jsonPathStep
+	op    string // 为空时判断字段是否存在
+	value interface{}
+}
+
+// ParseJSONPath 解析 JSONPath 模板
+func ParseJSONPath(template string) (*JSONPath, error) {
+	segments, err := splitTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	var stack [][]jsonPathNode
+	var rangeNodes []jsonPathNode
+	current := []jsonPathNode{}
+	for _, seg := range segments {
+		if !seg.expr {
+			current = append(current, jsonPathNode{text: seg.text, literal: true})
+			continue
+		}
+
+		expr := strings.TrimSpace(seg.text)
+		switch {
+		case expr == "end":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("JSONPath 中的 end 没有对应的 range")
+			}
+			node := rangeNodes[len(rangeNodes)-1]
+			node.children = current
+			rangeNodes = rangeNodes[:len(rangeNodes)-1]
+			current = append(stack[len(stack)-1], node)
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(expr, "range ") || strings.HasPrefix(expr, "range\t"):
+			node, err := parsePathNode(strings.TrimSpace(expr[len("range"):]))
+			if err != nil {
+				return nil, err
+			}
+			node.isRange = true
+			rangeNodes = append(rangeNodes, node)
+			stack = append(stack, current)
+			current = []jsonPathNode{}
+		case strings.HasPrefix(expr, `"`) || strings.HasPrefix(expr, "'"):
+			text, err := unquote(expr)
+			if err != nil {
+				return nil, fmt.Errorf("JSONPath 中的字符串 %s 不正确: %w", expr, err)
+			}
+			current = append(current, jsonPathNode{text: text, literal: true})
+		default:
+			node, err := parsePathNode(expr)
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, node)
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("JSONPath 中的 range 缺少 end")
+	}
+	return &JSONPath{nodes: current}, nil
+}
+
+type templateSegment struct {
+	text string
+	expr bool
+}
+
+// 将模板拆分为普通文本和 {} 中的表达式，表达式中的引号内允许出现 {}
+func splitTemplate(template string) ([]templateSegment, error) {
+	var segments []templateSegment
+	var text strings.Builder
+	for i := 0; i < len(template); i++ {
+		if template[i] != '{' {
+			text.WriteByte(template[i])
+			continue
+		}
+		if text.Len() > 0 {
+			segments = append(segments, templateSegment{text: text.String()})
+			text.Reset()
+		}
+
+		var quote byte
+		j := i + 1
+		for ; j < len(template); j++ {
+			c := template[j]
+			if quote != 0 {
+				if c == '\\' {
+					j++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			}
+			if c == '"' || c == '\'' {
+				quote = c
+			} else if c == '}' {
+				break
+			}
+		}
+		if j >= len(template) {
+			return nil, fmt.Errorf("JSONPath 模板中的 { 没有闭合")
+		}
+		segments = append(segments, templateSegment{text: template[i+1 : j], expr: true})
+		i = j
+	}
+	if text.Len() > 0 {
+		segments = append(segments, templateSegment{text: text.String()})
+	}
+	return segments, nil
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("缺少结束引号")
+		}
+		// 转为双引号字符串，\' 还原为单引号，双引号需要转义
+		s = `"` + strings.NewReplacer(`\'`, `'`, `"`, `\"`, `\\`, `\\`).Replace(s[1:len(s)-1]) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+func parsePathNode(expr string) (jsonPathNode, error) {
+	node := jsonPathNode{}
+	switch {
+	case strings.HasPrefix(expr, "$"):
+		node.fromRoot = true
+		expr = expr[1:]
+	case strings.HasPrefix(expr, "@"):
+		expr = expr[1:]
+	}
+	steps, err := parseSteps(expr)
+	if err != nil {
+		return node, err
+	}
+	node.path = steps
+	return node, nil
+}
+
+// 解析 .a.b[0]['c'][*]..d 形式的路径
+func parseSteps(expr string) ([]jsonPathStep, error) {
+	var steps []jsonPathStep
+	for i := 0; i < len(expr); {
+		switch {
+		case strings.HasPrefix(expr[i:], ".."):
+			name, n := readName(expr[i+2:])
+			if name == "" {
+				return nil, fmt.Errorf("JSONPath %s 中的 .. 后缺少字段名", expr)
+			}
+			steps = append(steps, jsonPathStep{kind: "recursive", name: name})
+			i += 2 + n
+		case expr[i] == '.':
+			if i+1 < len(expr) && expr[i+1] == '*' {
+				steps = append(steps, jsonPathStep{kind: "wildcard"})
+				i += 2
+				continue
+			}
+			name, n := readName(expr[i+1:])
+			i += 1 + n
+			if name != "" {
+				steps = append(steps, jsonPathStep{kind: "field", name: name})
+			}
+		case expr[i] == '[':
+			end := matchingBracket(expr, i)
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %s 中的 [ 没有闭合", expr)
+			}
+			step, err := parseBracket(strings.TrimSpace(expr[i+1 : end]))
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+			i = end + 1
+		default:
+			return nil, fmt.Errorf("JSONPath %s 不正确，位置 %d 处的字符 %q 无法识别", expr, i, expr[i])
+		}
+	}
+	return steps, nil
+}
+
+func readName(s string) (string, int) {
+	n := 0
+	for n < len(s) && s[n] != '.' && s[n] != '[' && s[n] != ' ' && s[n] != ')' {
+		n++
+	}
+	return s[:n], n
+}
+
+func matchingBracket(s string, start int) int {
+	depth := 0
+	var quote byte
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'':
+			quote = c
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(content string) (jsonPathStep, error) {
+	switch {
+	case content == "*":
+		return jsonPathStep{kind: "wildcard"}, nil
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, `"`):
+		name, err := unquote(content)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("JSONPath 字段名 %s 不正确: %w", content, err)
+		}
+		return jsonPathStep{kind: "field", name: name}, nil
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		filter, err := parseFilter(strings.TrimSpace(content[2 : len(content)-1]))
+		if err != nil {
+			return jsonPathStep{}, err
+		}
+		return jsonPathStep{kind: "filter", filter: filter}, nil
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 3)
+		step := jsonPathStep{kind: "slice"}
+		for i, target := range []**int{&step.start, &step.end} {
+			part := strings.TrimSpace(parts[i])
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return jsonPathStep{}, fmt.Errorf("JSONPath 下标 [%s] 不正确", content)
+			}
+			*target = &n
+		}
+		return step, nil
+	default:
+		n, err := strconv.Atoi(content)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("JSONPath 下标 [%s] 不正确", content)
+		}
+		return jsonPathStep{kind: "index", index: n}, nil
+	}
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// 解析 @.a.b == "x" 形式的过滤条件
+func parseFilter(expr string) (*jsonPathFilter, error) {
+	filter := &jsonPathFilter{}
+	left := expr
+	if idx, op := findFilterOp(expr); idx >= 0 {
+		filter.op = op
+		left = strings.TrimSpace(expr[:idx])
+		value, err := parseLiteral(strings.TrimSpace(expr[idx+len(op):]))
+		if err != nil {
+			return nil, err
+		}
+		filter.value = value
+	}
+
+	if !strings.HasPrefix(left, "@") {
+		return nil, fmt.Errorf("JSONPath 过滤条件 %s 必须以 @ 开头", expr)
+	}
+	steps, err := parseSteps(left[1:])
+	if err != nil {
+		return nil, err
+	}
+	filter.path = steps
+	return filter, nil
+}
+
+// 查找引号之外的第一个比较运算符
+func findFilterOp(expr string) (int, string) {
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '"' || c == '\'' {
+			quote = c
+			continue
+		}
+		for _, op := range filterOps {
+			if strings.HasPrefix(expr[i:], op) {
+				return i, op
+			}
+		}
+	}
+	return -1, ""
+}
+
+func parseLiteral(s string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		return unquote(s)
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null":
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("JSONPath 过滤条件中的值 %s 不正确", s)
+	}
+	return f, nil
+}
+
+// Execute 对 JSON 形式的数据执行模板
+func (j *JSONPath) Execute(buf *bytes.Buffer, data interface{}) error {
+	return executeNodes(buf, j.nodes, data, data)
+}
+
+func executeNodes(buf *bytes.Buffer, nodes []jsonPathNode, root, current interface{}) error {
+	for _, node := range nodes {
+		if node.literal {
+			buf.WriteString(node.text)
+			continue
+		}
+
+		start := current
+		if node.fromRoot {
+			start = root
+		}
+		results, err := evalSteps([]interface{}{start}, node.path, true)
+		if err != nil {
+			return err
+		}
+
+		if node.isRange {
+			for _, item := range results {
+				if err := executeNodes(buf, node.children, root, item); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		for i, result := range results {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			text, err := formatJSONValue(result)
+			if err != nil {
+				return err
+			}
+			buf.WriteString(text)
+		}
+	}
+	return nil
+}
+
+// 依次执行各步取值。strict 为 true 时，字段或下标在所有取值对象中都取不到值则返回错误，
+// 取值对象本身为空（如空列表的 [*]）时不报错
+func evalSteps(values []interface{}, steps []jsonPathStep, strict bool) ([]interface{}, error) {
+	for _, step := range steps {
+		var next []interface{}
+		for _, value := range values {
+			next = append(next, evalStep(value, step)...)
+		}
+		if strict && len(values) > 0 && len(next) == 0 {
+			switch step.kind {
+			case "field":
+				return nil, fmt.Errorf("字段 %s 不存在", step.name)
+			case "index":
+				return nil, fmt.Errorf("下标 [%d] 越界", step.index)
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func evalStep(value interface{}, step jsonPathStep) []interface{} {
+	switch step.kind {
+	case "field":
+		if m, ok := value.(map[string]interface{}); ok {
+			if v, ok := m[step.name]; ok {
+				return []interface{}{v}
+			}
+		}
+	case "recursive":
+		return recursiveFind(value, step.name)
+	case "wildcard":
+		return children(value)
+	case "index":
+		if list, ok := value.([]interface{}); ok {
+			i := step.index
+			if i < 0 {
+				i += len(list)
+			}
+			if i >= 0 && i < len(list) {
+				return []interface{}{list[i]}
+			}
+		}
+	case "slice":
+		if list, ok := value.([]interface{}); ok {
+			start, end := 0, len(list)
+			if step.start != nil {
+				start = clampIndex(*step.start, len(list))
+			}
+			if step.end != nil {
+				end = clampIndex(*step.end, len(list))
+			}
+			if start < end {
+				return list[start:end]
+			}
+		}
+	case "filter":
+		var matched []interface{}
+		for _, item := range children(value) {
+			if step.filter.match(item) {
+				matched = append(matched, item)
+			}
+		}
+		return matched
+	}
+	return nil
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+	return i
+}
+
+// 对象的所有值（按键排序）或数组的所有元素
+func children(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			result = append(result, v[key])
+		}
+		return result
+	}
+	return nil
+}
+
+func recursiveFind(value interface{}, name string) []interface{} {
+	var result []interface{}
+	if m, ok := value.(map[string]interface{}); ok {
+		if v, ok := m[name]; ok {
+			result = append(result, v)
+		}
+	}
+	for _, child := range children(value) {
+		result = append(result, recursiveFind(child, name)...)
+	}
+	return result
+}
+
+func (f *jsonPathFilter) match(item interface{}) bool {
+	results, _ := evalSteps([]interface{}{item}, f.path, false)
+	if f.op == "" {
+		return len(results) > 0 && results[0] != nil && results[0] != false
+	}
+	if len(results) == 0 {
+		return false
+	}
+	return compareJSON(results[0], f.op, f.value)
+}
+
+func compareJSON(left interface{}, op string, right interface{}) bool {
+	if l, ok := toFloat(left); ok {
+		if r, ok := toFloat(right); ok {
+			switch op {
+			case "==":
+				return l == r
+			case "!=":
+				return l != r
+			case "<":
+				return l < r
+			case ">":
+				return l > r
+			case "<=":
+				return l <= r
+			case ">=":
+				return l >= r
+			}
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			switch op {
+			case "==":
+				return l == r
+			case "!=":
+				return l != r
+			case "<":
+				return l < r
+			case ">":
+				return l > r
+			case "<=":
+				return l <= r
+			case ">=":
+				return l >= r
+			}
+		}
+	}
+	switch op {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	}
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// 字符串原样输出，其他值输出为 JSON
+func formatJSONValue(v interface{}) (string, error) {
+	switch value := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,144 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonPathData = `{
+  "items": [
+    {"dataId": "app.yaml", "group": "DEFAULT_GROUP", "type": "yaml", "size": 120, "tags": ["a", "b"], "enabled": true},
+    {"dataId": "db.properties", "group": "DB", "type": "properties", "size": 30, "tags": [], "enabled": false},
+    {"dataId": "it's.json", "group": "DEFAULT_GROUP", "type": "json", "size": 75, "desc": null}
+  ],
+  "meta": {"page.size": 3, "owner": {"name": "ops"}}
+}`
+
+func decodeJSONPathData(t *testing.T) interface{} {
+	t.Helper()
+	decoder := json.NewDecoder(strings.NewReader(jsonPathData))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		t.Fatal(err)
+	}
+	return value
+}
+
+func TestJSONPathExecute(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"field", "{.meta.owner.name}", "ops"},
+		{"root", "{$.meta.owner.name}", "ops"},
+		{"number", "{.items[0].size}", "120"},
+		{"bool", "{.items[1].enabled}", "false"},
+		{"null", "{.items[2].desc}", ""},
+		{"object", "{.meta.owner}", `{"name":"ops"}`},
+		{"text around expressions", "owner={.meta.owner.name};", "owner=ops;"},
+		{"index", "{.items[1].dataId}", "db.properties"},
+		{"negative index", "{.items[-1].dataId}", "it's.json"},
+		{"wildcard", "{.items[*].group}", "DEFAULT_GROUP DB DEFAULT_GROUP"},
+		{"dot wildcard", "{.meta.owner.*}", "ops"},
+		{"slice", "{.items[0:2].dataId}", "app.yaml db.properties"},
+		{"open slice", "{.items[1:].dataId}", "db.properties it's.json"},
+		{"negative slice", "{.items[-2:].size}", "30 75"},
+		{"slice out of range", "{.items[1:10].size}", "30 75"},
+		{"recursive", "{..name}", "ops"},
+		{"empty list", "{.items[1].tags[*]}", ""},
+		{"quoted field", "{.meta['page.size']}", "3"},
+		{"double quoted field", `{.meta["owner"].name}`, "ops"},
+		{"escaped backslash", `{'a\\b'}`, `a\b`},
+		{"string literal", `{.items[0].dataId}{"\t"}{.items[0].group}{'\n'}`, "app.yaml\tDEFAULT_GROUP\n"},
+		{"brace in literal", `{"{"}{.meta.owner.name}{"}"}`, "{ops}"},
+		{"filter string", `{.items[?(@.type == "json")].dataId}`, "it's.json"},
+		{"filter single quotes", `{.items[?(@.dataId == 'it\'s.json')].size}`, "75"},
+		{"filter number", "{.items[?(@.size > 50)].dataId}", "app.yaml it's.json"},
+		{"filter not equal", "{.items[?(@.group != 'DEFAULT_GROUP')].dataId}", "db.properties"},
+		{"filter bool", "{.items[?(@.enabled == true)].dataId}", "app.yaml"},
+		{"filter exists", "{.items[?(@.tags)].dataId}", "app.yaml db.properties"},
+		{"filter missing field", `{.items[?(@.missing == "x")].dataId}`, ""},
+		{"range", `{range .items[*]}{.dataId}{"="}{.size}{"\n"}{end}`, "app.yaml=120\ndb.properties=30\nit's.json=75\n"},
+		{"nested range", `{range .items[0:1]}{range .tags[*]}[{@}]{end}{end}`, "[a][b]"},
+		{"range with root", `{range .items[0:2]}{.dataId}@{$.meta.owner.name} {end}`, "app.yaml@ops db.properties@ops "},
+		{"range filter", `{range .items[?(@.type=="yaml")]}{.dataId}{end}`, "app.yaml"},
+	}
+
+	data := decodeJSONPathData(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jp, err := ParseJSONPath(tt.template)
+			if err != nil {
+				t.Fatalf("ParseJSONPath(%q) error: %v", tt.template, err)
+			}
+			var buf bytes.Buffer
+			if err := jp.Execute(&buf, data); err != nil {
+				t.Fatalf("Execute(%q) error: %v", tt.template, err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Execute(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathExecuteErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"missing field", "{.meta.missing}", "字段 missing 不存在"},
+		{"missing nested field", "{.meta.owner.name.first}", "字段 first 不存在"},
+		{"missing in all items", "{.items[*].missing}", "字段 missing 不存在"},
+		{"missing in range", "{range .items[*]}{.desc}{end}", "字段 desc 不存在"},
+		{"index out of range", "{.items[5].dataId}", "下标 [5] 越界"},
+		{"index on object", "{.meta[0]}", "下标 [0] 越界"},
+	}
+
+	data := decodeJSONPathData(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jp, err := ParseJSONPath(tt.template)
+			if err != nil {
+				t.Fatalf("ParseJSONPath(%q) error: %v", tt.template, err)
+			}
+			var buf bytes.Buffer
+			err = jp.Execute(&buf, data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute(%q) error = %v, want %q", tt.template, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPathErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{"unclosed brace", "{.items"},
+		{"end without range", "{.items}{end}"},
+		{"range without end", "{range .items[*]}{.dataId}"},
+		{"unclosed bracket", "{.items[0}"},
+		{"bad index", "{.items[x]}"},
+		{"bad slice", "{.items[1:y]}"},
+		{"unterminated quote", `{.meta['owner]}`},
+		{"filter without @", `{.items[?(.type == "yaml")]}`},
+		{"bad filter value", "{.items[?(@.size > big)]}"},
+		{"missing recursive name", "{..}"},
+		{"unknown character", "{.items[0]x}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJSONPath(tt.template); err == nil {
+				t.Errorf("ParseJSONPath(%q) succeeded, want error", tt.template)
+			}
+		})
+	}
+}
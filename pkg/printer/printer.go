@@ -0,0 +1,257 @@
+// Package printer 按 --output 指定的格式输出命令结果
+package printer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 支持的输出格式
+const (
+	FormatTable = "table"
+	FormatWide  = "wide"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+	FormatName  = "name"
+	FormatCSV   = "csv"
+
+	// 带参数的格式，如 template={{.DataID}}、jsonpath={.items[*].dataId}
+	FormatTemplate = "template"
+	FormatJSONPath = "jsonpath"
+)
+
+// Formats 支持的输出格式列表，不包括带参数的格式
+var Formats = []string{FormatTable, FormatWide, FormatJSON, FormatYAML, FormatName, FormatCSV}
+
+// 拆分 template=... 形式的格式名和参数，go-template 是 template 的别名
+func splitFormat(format string) (string, string, bool) {
+	name, arg, ok := strings.Cut(format, "=")
+	if name == "go-template" {
+		name = FormatTemplate
+	}
+	return name, arg, ok
+}
+
+// Output 命令的输出内容，不同格式使用其中不同的部分
+type Output struct {
+	Object interface{}                        // 结构化对象，用于 json、yaml 格式；列表会包装为 {"items": [...]}
+	Table  *Table                             // 表格，用于 table、wide、csv 格式
+	Names  []string                           // name 格式每行输出一个名称，为空时使用表格的第一列
+	Text   func(w io.Writer, wide bool) error // 自定义的文本输出，设置时代替 Table 用于 table、wide 格式
+	Empty  string                             // 表格没有数据时在 table、wide 格式中输出的提示
+	Footer string                             // table、wide 格式中表格之后输出的说明，如分页信息
+}
+
+// ValidateFormat 检查输出格式是否受支持，模板和 JSONPath 会在此时解析
+func ValidateFormat(format string) error {
+	if name, arg, ok := splitFormat(format); ok {
+		switch name {
+		case FormatTemplate:
+			_, err := ParseTemplate(arg)
+			return err
+		case FormatJSONPath:
+			_, err := ParseJSONPath(arg)
+			return err
+		}
+	}
+	for _, f := range Formats {
+		if format == f {
+			return nil
+		}
+	}
+	return fmt.Errorf("不支持的输出格式: %s，可选值: %s, %s=..., %s=...",
+		format, strings.Join(Formats, ", "), FormatTemplate, FormatJSONPath)
+}
+
+// Print 按指定格式输出
+func Print(w io.Writer, format string, out *Output) error {
+	if name, arg, ok := splitFormat(format); ok {
+		switch name {
+		case FormatTemplate:
+			return printTemplate(w, arg, out.Object)
+		case FormatJSONPath:
+			return printJSONPath(w, arg, out.Object)
+		}
+	}
+
+	switch format {
+	case FormatTable, FormatWide:
+		return printText(w, format == FormatWide, out)
+	case FormatJSON:
+		data, err := json.MarshalIndent(wrapList(out.Object), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case FormatYAML:
+		data, err := ToYAML(wrapList(out.Object))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	case FormatName:
+		names := out.Names
+		if names == nil && out.Table != nil {
+			for _, row := range out.Table.Rows {
+				if len(row) > 0 {
+					names = append(names, row[0])
+				}
+			}
+		} else if names == nil && out.Object != nil && out.Table == nil {
+			return fmt.Errorf("该命令不支持 name 格式")
+		}
+		for _, name := range names {
+			if _, err := fmt.Fprintln(w, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatCSV:
+		if out.Table == nil {
+			return fmt.Errorf("该命令不支持 csv 格式")
+		}
+		return printCSV(w, out.Table)
+	default:
+		return ValidateFormat(format)
+	}
+}
+
+func printText(w io.Writer, wide bool, out *Output) error {
+	if out.Text != nil {
+		if err := out.Text(w, wide); err != nil {
+			return err
+		}
+	} else if out.Table != nil {
+		if len(out.Table.Rows) == 0 && out.Empty != "" {
+			_, err := fmt.Fprintln(w, out.Empty)
+			return err
+		}
+		if err := renderTable(w, out.Table, wide); err != nil {
+			return err
+		}
+	} else {
+		data, err := ToYAML(wrapList(out.Object))
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	if out.Footer != "" {
+		_, err := fmt.Fprintf(w, "\n%s\n", out.Footer)
+		return err
+	}
+	return nil
+}
+
+// 模板直接使用 Go 对象，列表不包装，可以用 {{range .}} 遍历
+func printTemplate(w io.Writer, text string, obj interface{}) error {
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(w, obj); err != nil {
+		return fmt.Errorf("执行模板失败: %w", err)
+	}
+	return nil
+}
+
+// JSONPath 作用于 json 格式输出的对象，列表同样包装为 {"items": [...]}
+func printJSONPath(w io.Writer, text string, obj interface{}) error {
+	jp, err := ParseJSONPath(text)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(wrapList(obj))
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := jp.Execute(&buf, value); err != nil {
+		return fmt.Errorf("执行JSONPath失败: %w", err)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func printCSV(w io.Writer, t *Table) error {
+	writer := csv.NewWriter(w)
+	headers := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		headers[i] = column.Header
+	}
+	if err := writer.Write(headers); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// 列表包装为 {"items": [...]}，与 kubectl 的 List 类似，便于 jq 等工具统一处理
+func wrapList(obj interface{}) interface{} {
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 || v.Kind() == reflect.Array {
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			obj = []interface{}{}
+		}
+		return map[string]interface{}{"items": obj}
+	}
+	return obj
+}
+
+// ToYAML 将对象转为 YAML。先转为 JSON 再转为 YAML，使字段名和顺序与 json 格式一致
+func ToYAML(obj interface{}) ([]byte, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	clearStyle(&node)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// JSON 使用引号和流式风格，转为 YAML 的块风格
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearStyle(child)
+	}
+}
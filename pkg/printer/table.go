@@ -0,0 +1,160 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// Column 表格的一列
+type Column struct {
+	Header string
+	Wide   bool // 只在 wide 和 csv 格式中输出
+}
+
+// Table 表格形式的输出，每行的单元格与 Columns 一一对应
+type Table struct {
+	Columns []Column
+	Rows    [][]string
+}
+
+// NewTable 创建表格，表头以 * 结尾的列只在 wide 格式中输出
+func NewTable(headers ...string) *Table {
+	t := &Table{}
+	for _, header := range headers {
+		if strings.HasSuffix(header, "*") {
+			t.Columns = append(t.Columns, Column{Header: strings.TrimSuffix(header, "*"), Wide: true})
+		} else {
+			t.Columns = append(t.Columns, Column{Header: header})
+		}
+	}
+	return t
+}
+
+// AddRow 添加一行
+func (t *Table) AddRow(cells ...interface{}) {
+	row := make([]string, len(cells))
+	for i, cell := range cells {
+		row[i] = fmt.Sprint(cell)
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// 需要输出的列的下标
+func (t *Table) visibleColumns(wide bool) []int {
+	var columns []int
+	for i, column := range t.Columns {
+		if wide || !column.Wide {
+			columns = append(columns, i)
+		}
+	}
+	return columns
+}
+
+// DisplayWidth 字符串在终端中的显示宽度，东亚宽字符和全角字符占两列
+func DisplayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// 用空格将字符串补齐到指定的显示宽度
+func pad(s string, w int) string {
+	if n := DisplayWidth(s); n < w {
+		return s + strings.Repeat(" ", w-n)
+	}
+	return s
+}
+
+// 表格中的单元格只占一行
+func singleLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\t", " ").Replace(s)
+}
+
+func renderTable(w io.Writer, t *Table, wide bool) error {
+	columns := t.visibleColumns(wide)
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = DisplayWidth(t.Columns[c].Header)
+		for _, row := range t.Rows {
+			if c < len(row) {
+				if n := DisplayWidth(singleLine(row[c])); n > widths[i] {
+					widths[i] = n
+				}
+			}
+		}
+	}
+
+	const gap = "   "
+	writeRow := func(cells []string) error {
+		var b strings.Builder
+		for i, cell := range cells {
+			if i == len(cells)-1 {
+				b.WriteString(cell)
+			} else {
+				b.WriteString(pad(cell, widths[i]))
+				b.WriteString(gap)
+			}
+		}
+		_, err := fmt.Fprintln(w, strings.TrimRight(b.String(), " "))
+		return err
+	}
+
+	headers := make([]string, len(columns))
+	total := 0
+	for i, c := range columns {
+		headers[i] = t.Columns[c].Header
+		total += widths[i]
+	}
+	total += len(gap) * (len(columns) - 1)
+	if err := writeRow(headers); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, strings.Repeat("-", total)); err != nil {
+		return err
+	}
+
+	for _, row := range t.Rows {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			if c < len(row) {
+				cells[i] = singleLine(row[c])
+			}
+		}
+		if err := writeRow(cells); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Field 以“名称: 值”形式输出的一项
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// PrintFields 逐行输出各项，名称按显示宽度对齐
+func PrintFields(w io.Writer, fields []Field) error {
+	nameWidth := 0
+	for _, f := range fields {
+		if n := DisplayWidth(f.Name) + 1; n > nameWidth {
+			nameWidth = n
+		}
+	}
+	for _, f := range fields {
+		if _, err := fmt.Fprintf(w, "%s %v\n", pad(f.Name+":", nameWidth), f.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package printer
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// 模板中可用的辅助函数
+var templateFuncs = template.FuncMap{
+	// md5 计算字符串的 MD5，与 Nacos 配置的 md5 一致
+	"md5": func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	},
+	// yaml 将对象转为 YAML
+	"yaml": func(v interface{}) (string, error) {
+		data, err := ToYAML(v)
+		return string(data), err
+	},
+	// json 将对象转为单行 JSON
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// indent 在每行前加上 n 个空格
+	"indent": func(n int, s string) string {
+		prefix := strings.Repeat(" ", n)
+		return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+	},
+	// truncate 截取前 n 个字符
+	"truncate": func(n int, s string) string {
+		runes := []rune(s)
+		if n < 0 || len(runes) <= n {
+			return s
+		}
+		return string(runes[:n])
+	},
+}
+
+// ParseTemplate 解析 Go 模板，模板中的字段名与 Go 结构体一致，如 {{.DataID}}
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败: %w", err)
+	}
+	return tmpl, nil
+}
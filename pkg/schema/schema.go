@@ -0,0 +1,492 @@
+// Package schema 按 JSON Schema 检查配置内容。支持常用的关键字：type、enum、const、properties、required、
+// additionalProperties、patternProperties、items、minItems、maxItems、uniqueItems、minLength、maxLength、pattern、
+// minimum、maximum、exclusiveMinimum、exclusiveMaximum、multipleOf、minProperties、maxProperties、
+// allOf、anyOf、oneOf、not 以及文件内的 $ref
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"nacos-cli/pkg/configtree"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema 从文件读取的 JSON Schema
+type Schema struct {
+	Path string
+	root interface{}
+}
+
+// Violation 一处不符合 schema 的地方，Path 为键路径，如 server.port
+type Violation struct {
+	Path string `json:"path"`
+	Msg  string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Msg
+}
+
+// ValidationError 不符合 schema 时返回的错误，包含所有违反的地方
+type ValidationError struct {
+	Schema     string
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "不符合 schema %s:", e.Schema)
+	for _, v := range e.Violations {
+		b.WriteString("\n  ")
+		b.WriteString(v.String())
+	}
+	return b.String()
+}
+
+// Load 读取 JSON 或 yaml 格式的 schema 文件
+func Load(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 schema 文件失败: %w", err)
+	}
+	var root interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("解析 schema 文件 %s 失败: %w", path, err)
+	}
+	switch root.(type) {
+	case map[string]interface{}, bool:
+	default:
+		return nil, fmt.Errorf("schema 文件 %s 的内容应为对象", path)
+	}
+	return &Schema{Path: path, root: root}, nil
+}
+
+// Validate 检查已解析的配置，返回所有不符合的地方
+func (s *Schema) Validate(instance interface{}) []Violation {
+	v := &validator{root: s.root}
+	v.validate(s.root, normalize(instance), nil)
+	return v.violations
+}
+
+// ValidateContent 按配置类型解析配置内容后检查，properties 配置按键名中的点展开为嵌套的对象。
+// 不符合时返回 *ValidationError
+func (s *Schema) ValidateContent(configType string, content []byte) error {
+	instance, err := configtree.ParseNested(configType, content)
+	if err != nil {
+		return fmt.Errorf("解析配置失败: %w", err)
+	}
+	if violations := s.Validate(instance); len(violations) > 0 {
+		return &ValidationError{Schema: s.Path, Violations: violations}
+	}
+	return nil
+}
+
+type validator struct {
+	root       interface{}
+	violations []Violation
+	depth      int
+}
+
+func (v *validator) report(path []configtree.Segment, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Path: formatPath(path), Msg: fmt.Sprintf(format, args...)})
+}
+
+func formatPath(path []configtree.Segment) string {
+	if len(path) == 0 {
+		return "(根)"
+	}
+	return configtree.FormatPath(path)
+}
+
+// 检查 instance 是否符合 schema，不符合的地方记录到 violations
+func (v *validator) validate(schema interface{}, instance interface{}, path []configtree.Segment) {
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			v.report(path, "schema 不允许任何值")
+		}
+		return
+	case map[string]interface{}:
+		v.validateObject(s, instance, path)
+	}
+}
+
+func (v *validator) validateObject(s map[string]interface{}, instance interface{}, path []configtree.Segment) {
+	if ref, ok := s["$ref"].(string); ok {
+		target, err := v.resolve(ref)
+		if err != nil {
+			v.report(path, "%v", err)
+			return
+		}
+		// 防止循环引用导致无限递归
+		if v.depth > 100 {
+			v.report(path, "$ref 嵌套过深: %s", ref)
+			return
+		}
+		v.depth++
+		v.validate(target, instance, path)
+		v.depth--
+	}
+
+	if t, ok := s["type"]; ok && !matchesType(t, instance) {
+		v.report(path, "类型应为 %s，实际为 %s", typeNames(t), typeOf(instance))
+		return
+	}
+	if enum, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if equal(normalize(e), instance) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.report(path, "值应为以下之一: %s", describeValues(enum))
+		}
+	}
+	if c, ok := s["const"]; ok && !equal(normalize(c), instance) {
+		v.report(path, "值应为 %s", describe(c))
+	}
+
+	switch value := instance.(type) {
+	case map[string]interface{}:
+		v.validateProperties(s, value, path)
+	case []interface{}:
+		v.validateItems(s, value, path)
+	case string:
+		v.validateString(s, value, path)
+	default:
+		if n, ok := toFloat(value); ok {
+			v.validateNumber(s, n, path)
+		}
+	}
+
+	if all, ok := s["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			v.validate(sub, instance, path)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		if v.countMatches(anyOf, instance, path) == 0 {
+			v.report(path, "不符合 anyOf 中的任何一个 schema")
+		}
+	}
+	if oneOf, ok := s["oneOf"].([]interface{}); ok {
+		if n := v.countMatches(oneOf, instance, path); n != 1 {
+			v.report(path, "应恰好符合 oneOf 中的一个 schema，实际符合 %d 个", n)
+		}
+	}
+	if not, ok := s["not"]; ok {
+		if v.matches(not, instance, path) {
+			v.report(path, "不应符合 not 中的 schema")
+		}
+	}
+}
+
+func (v *validator) validateProperties(s map[string]interface{}, value map[string]interface{}, path []configtree.Segment) {
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, r := range required {
+			name := fmt.Sprint(r)
+			if _, ok := value[name]; !ok {
+				v.report(child(path, name), "缺少必需的属性")
+			}
+		}
+	}
+	if n, ok := toFloat(s["minProperties"]); ok && float64(len(value)) < n {
+		v.report(path, "属性个数应不少于 %v，实际为 %d", n, len(value))
+	}
+	if n, ok := toFloat(s["maxProperties"]); ok && float64(len(value)) > n {
+		v.report(path, "属性个数应不多于 %v，实际为 %d", n, len(value))
+	}
+
+	properties, _ := s["properties"].(map[string]interface{})
+	patterns, _ := s["patternProperties"].(map[string]interface{})
+	additional, hasAdditional := s["additionalProperties"]
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		matched := false
+		if sub, ok := properties[key]; ok {
+			matched = true
+			v.validate(sub, value[key], child(path, key))
+		}
+		for pattern, sub := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				v.report(path, "patternProperties 中的正则表达式 %s 无效: %v", pattern, err)
+				continue
+			}
+			if re.MatchString(key) {
+				matched = true
+				v.validate(sub, value[key], child(path, key))
+			}
+		}
+		if matched || !hasAdditional {
+			continue
+		}
+		if allowed, ok := additional.(bool); ok {
+			if !allowed {
+				v.report(child(path, key), "不允许的属性")
+			}
+			continue
+		}
+		v.validate(additional, value[key], child(path, key))
+	}
+}
+
+func (v *validator) validateItems(s map[string]interface{}, items []interface{}, path []configtree.Segment) {
+	if n, ok := toFloat(s["minItems"]); ok && float64(len(items)) < n {
+		v.report(path, "元素个数应不少于 %v，实际为 %d", n, len(items))
+	}
+	if n, ok := toFloat(s["maxItems"]); ok && float64(len(items)) > n {
+		v.report(path, "元素个数应不多于 %v，实际为 %d", n, len(items))
+	}
+	if unique, _ := s["uniqueItems"].(bool); unique {
+		for i := range items {
+			for j := 0; j < i; j++ {
+				if equal(items[i], items[j]) {
+					v.report(index(path, i), "与第 %d 个元素重复", j)
+				}
+			}
+		}
+	}
+	if sub, ok := s["items"]; ok {
+		for i, item := range items {
+			v.validate(sub, item, index(path, i))
+		}
+	}
+}
+
+func (v *validator) validateString(s map[string]interface{}, value string, path []configtree.Segment) {
+	length := float64(utf8.RuneCountInString(value))
+	if n, ok := toFloat(s["minLength"]); ok && length < n {
+		v.report(path, "长度应不小于 %v，实际为 %v", n, length)
+	}
+	if n, ok := toFloat(s["maxLength"]); ok && length > n {
+		v.report(path, "长度应不大于 %v，实际为 %v", n, length)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.report(path, "pattern 中的正则表达式 %s 无效: %v", pattern, err)
+		} else if !re.MatchString(value) {
+			v.report(path, "值 %q 不匹配 %s", value, pattern)
+		}
+	}
+}
+
+func (v *validator) validateNumber(s map[string]interface{}, n float64, path []configtree.Segment) {
+	if min, ok := toFloat(s["minimum"]); ok {
+		// draft-04 中 exclusiveMinimum 为布尔值，表示 minimum 不含等于
+		if exclusive, _ := s["exclusiveMinimum"].(bool); exclusive && n <= min {
+			v.report(path, "应大于 %v，实际为 %v", min, n)
+		} else if n < min {
+			v.report(path, "应不小于 %v，实际为 %v", min, n)
+		}
+	}
+	if max, ok := toFloat(s["maximum"]); ok {
+		if exclusive, _ := s["exclusiveMaximum"].(bool); exclusive && n >= max {
+			v.report(path, "应小于 %v，实际为 %v", max, n)
+		} else if n > max {
+			v.report(path, "应不大于 %v，实际为 %v", max, n)
+		}
+	}
+	if min, ok := toFloat(s["exclusiveMinimum"]); ok && n <= min {
+		v.report(path, "应大于 %v，实际为 %v", min, n)
+	}
+	if max, ok := toFloat(s["exclusiveMaximum"]); ok && n >= max {
+		v.report(path, "应小于 %v，实际为 %v", max, n)
+	}
+	if m, ok := toFloat(s["multipleOf"]); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.report(path, "应为 %v 的倍数，实际为 %v", m, n)
+		}
+	}
+}
+
+// 符合 schemas 中的几个，不记录不符合的地方
+func (v *validator) countMatches(schemas []interface{}, instance interface{}, path []configtree.Segment) int {
+	count := 0
+	for _, sub := range schemas {
+		if v.matches(sub, instance, path) {
+			count++
+		}
+	}
+	return count
+}
+
+func (v *validator) matches(schema, instance interface{}, path []configtree.Segment) bool {
+	sub := &validator{root: v.root, depth: v.depth}
+	sub.validate(schema, instance, path)
+	return len(sub.violations) == 0
+}
+
+// 解析文件内的 $ref，如 #/definitions/port 或 #/$defs/port
+func (v *validator) resolve(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("不支持引用其他文件的 $ref: %s", ref)
+	}
+	node := v.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch n := node.(type) {
+		case map[string]interface{}:
+			next, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("$ref 无法解析: %s", ref)
+			}
+			node = next
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, fmt.Errorf("$ref 无法解析: %s", ref)
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("$ref 无法解析: %s", ref)
+		}
+	}
+	return node, nil
+}
+
+func child(path []configtree.Segment, key string) []configtree.Segment {
+	return append(append([]configtree.Segment{}, path...), configtree.Segment{Key: key})
+}
+
+func index(path []configtree.Segment, i int) []configtree.Segment {
+	return append(append([]configtree.Segment{}, path...), configtree.Segment{Index: i, IsIndex: true})
+}
+
+// 将 yaml、json 解析出的值统一为 map[string]interface{}、[]interface{}、string、bool、float64 和 nil
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = normalize(item)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = normalize(item)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = normalize(item)
+		}
+		return result
+	default:
+		if n, ok := toFloat(value); ok {
+			return n
+		}
+		return value
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func matchesType(t interface{}, instance interface{}) bool {
+	actual := typeOf(instance)
+	check := func(name string) bool {
+		return name == actual || name == "number" && actual == "integer"
+	}
+	switch types := t.(type) {
+	case string:
+		return check(types)
+	case []interface{}:
+		for _, name := range types {
+			if check(fmt.Sprint(name)) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func typeNames(t interface{}) string {
+	if types, ok := t.([]interface{}); ok {
+		names := make([]string, len(types))
+		for i, name := range types {
+			names[i] = fmt.Sprint(name)
+		}
+		return strings.Join(names, " 或 ")
+	}
+	return fmt.Sprint(t)
+}
+
+func equal(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func describe(value interface{}) string {
+	data, err := json.Marshal(normalize(value))
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func describeValues(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = describe(value)
+	}
+	return strings.Join(parts, ", ")
+}
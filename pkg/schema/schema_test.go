@@ -0,0 +1,213 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func loadSchema(t *testing.T, content string) *Schema {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schema.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	return s
+}
+
+func violations(t *testing.T, s *Schema, instance string) []string {
+	t.Helper()
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(instance), &value); err != nil {
+		t.Fatal(err)
+	}
+	var result []string
+	for _, v := range s.Validate(value) {
+		result = append(result, v.String())
+	}
+	return result
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		want     []string
+	}{
+		{
+			name:     "valid",
+			schema:   "type: object\nproperties:\n  port: {type: integer}\n  name: {type: string}\n",
+			instance: "port: 8080\nname: order\n",
+		},
+		{
+			name:     "type",
+			schema:   "properties:\n  port: {type: integer}\n  ratio: {type: number}\n",
+			instance: "port: \"8080\"\nratio: 1\n",
+			want:     []string{"port: 类型应为 integer，实际为 string"},
+		},
+		{
+			name:     "type list",
+			schema:   "properties:\n  a: {type: [string, 'null']}\n",
+			instance: "a: 1\n",
+			want:     []string{"a: 类型应为 string 或 null，实际为 integer"},
+		},
+		{
+			name:     "required and nested path",
+			schema:   "properties:\n  spring:\n    properties:\n      datasource:\n        required: [url]\n",
+			instance: "spring:\n  datasource:\n    username: root\n",
+			want:     []string{"spring.datasource.url: 缺少必需的属性"},
+		},
+		{
+			name:     "enum and const",
+			schema:   "properties:\n  level: {enum: [debug, info]}\n  version: {const: 2}\n",
+			instance: "level: trace\nversion: 2\n",
+			want:     []string{`level: 值应为以下之一: "debug", "info"`},
+		},
+		{
+			name:     "additionalProperties",
+			schema:   "properties:\n  a: {}\nadditionalProperties: false\n",
+			instance: "a: 1\nb: 2\n",
+			want:     []string{"b: 不允许的属性"},
+		},
+		{
+			name:     "patternProperties",
+			schema:   "patternProperties:\n  '^x-': {type: string}\nadditionalProperties: {type: integer}\n",
+			instance: "x-a: 1\nb: c\n",
+			want:     []string{"b: 类型应为 integer，实际为 string", "x-a: 类型应为 string，实际为 integer"},
+		},
+		{
+			name:     "items",
+			schema:   "properties:\n  servers:\n    minItems: 1\n    uniqueItems: true\n    items: {type: string, pattern: '^[a-z]+:[0-9]+$'}\n",
+			instance: "servers: [a:1, B:2, a:1]\n",
+			want:     []string{`servers[2]: 与第 0 个元素重复`, `servers[1]: 值 "B:2" 不匹配 ^[a-z]+:[0-9]+$`},
+		},
+		{
+			name:     "string length counts characters",
+			schema:   "properties:\n  name: {minLength: 3, maxLength: 4}\n",
+			instance: "name: 订单\n",
+			want:     []string{"name: 长度应不小于 3，实际为 2"},
+		},
+		{
+			name:     "number range",
+			schema:   "properties:\n  port: {minimum: 1, maximum: 65535}\n  ratio: {exclusiveMinimum: 0, multipleOf: 0.5}\n",
+			instance: "port: 70000\nratio: 0.75\n",
+			want:     []string{"port: 应不大于 65535，实际为 70000", "ratio: 应为 0.5 的倍数，实际为 0.75"},
+		},
+		{
+			name:     "draft-04 exclusive minimum",
+			schema:   "properties:\n  n: {minimum: 0, exclusiveMinimum: true}\n",
+			instance: "n: 0\n",
+			want:     []string{"n: 应大于 0，实际为 0"},
+		},
+		{
+			name:     "anyOf oneOf not",
+			schema:   "properties:\n  a: {anyOf: [{type: string}, {type: boolean}]}\n  b: {oneOf: [{type: integer}, {type: number}]}\n  c: {not: {type: 'null'}}\n",
+			instance: "a: 1\nb: 1\nc: null\n",
+			want:     []string{"a: 不符合 anyOf 中的任何一个 schema", "b: 应恰好符合 oneOf 中的一个 schema，实际符合 2 个", "c: 不应符合 not 中的 schema"},
+		},
+		{
+			name:     "ref",
+			schema:   "definitions:\n  port: {type: integer, maximum: 65535}\nproperties:\n  port: {$ref: '#/definitions/port'}\n",
+			instance: "port: 99999\n",
+			want:     []string{"port: 应不大于 65535，实际为 99999"},
+		},
+		{
+			name:     "unresolved ref",
+			schema:   "properties:\n  port: {$ref: '#/definitions/missing'}\n",
+			instance: "port: 1\n",
+			want:     []string{"port: $ref 无法解析: #/definitions/missing"},
+		},
+		{
+			name:     "false schema",
+			schema:   "properties:\n  removed: false\n",
+			instance: "removed: 1\n",
+			want:     []string{"removed: schema 不允许任何值"},
+		},
+		{
+			name:     "root",
+			schema:   "type: object\n",
+			instance: "[1]\n",
+			want:     []string{"(根): 类型应为 object，实际为 array"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := violations(t, loadSchema(t, tt.schema), tt.instance)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"list.yaml": "- a\n", "invalid.yaml": "a: [\n"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load(%s) succeeded, want error", name)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Load(missing.yaml) succeeded, want error")
+	}
+}
+
+func TestValidateContent(t *testing.T) {
+	s := loadSchema(t, `properties:
+  server:
+    properties:
+      port: {type: integer}
+  spring:
+    properties:
+      profiles: {type: string}
+`)
+
+	tests := []struct {
+		name, configType, content string
+		want                      []string
+	}{
+		{"yaml", "yaml", "server:\n  port: 8080\n", nil},
+		{"json", "json", `{"server": {"port": "x"}}`, []string{"server.port: 类型应为 integer，实际为 string"}},
+		{"properties typed values", "properties", "server.port=8080\n", nil},
+		{"properties violation", "properties", "server.port=abc\n", []string{"server.port: 类型应为 integer，实际为 string"}},
+		// 同时存在 a=1 和 a.b=2 时保留值，而不是解析失败
+		{"properties value and nested key", "properties", "spring.profiles=dev\nspring.profiles.active=dev\n", nil},
+		{"properties nested key before value", "properties", "server.port.x=1\nserver.port=abc\n", []string{"server.port: 类型应为 integer，实际为 string"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateContent(tt.configType, []byte(tt.content))
+			var got []string
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				for _, v := range validationErr.Violations {
+					got = append(got, v.String())
+				}
+			} else if err != nil {
+				t.Fatalf("ValidateContent() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := s.ValidateContent("yaml", []byte("a: [\n")); err == nil || errors.As(err, new(*ValidationError)) {
+		t.Errorf("ValidateContent() with invalid yaml error = %v, want parse error", err)
+	}
+}
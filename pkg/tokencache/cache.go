@@ -0,0 +1,147 @@
+package tokencache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 令牌剩余有效期不足该时长时视为过期，避免请求途中失效
+const expiryMargin = 60 * time.Second
+
+// Entry 缓存的令牌
+type Entry struct {
+	Token       string `json:"token"`
+	Expiry      int64  `json:"expiry"` // Unix时间戳
+	GlobalAdmin bool   `json:"globalAdmin"`
+}
+
+// Valid 判断令牌是否仍然可用
+func (e Entry) Valid() bool {
+	return e.Token != "" && time.Unix(e.Expiry, 0).After(time.Now().Add(expiryMargin))
+}
+
+// Cache 按服务器地址和用户名保存令牌的文件缓存，文件权限为 0600，
+// 写入时先写临时文件再重命名，多个进程可通过 Lock 串行化登录
+type Cache struct {
+	Path string
+}
+
+// New 创建令牌缓存
+func New(path string) *Cache {
+	return &Cache{Path: path}
+}
+
+// Get 返回未过期的令牌
+func (c *Cache) Get(server, username string) (Entry, bool, error) {
+	entries, err := c.load()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	entry, ok := entries[key(server, username)]
+	if !ok || !entry.Valid() {
+		return Entry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+// Put 保存令牌，同时清理已过期的条目。读取和写回之间没有加锁，调用方需持有 Lock 返回的锁，
+// 否则并发写入的其他进程保存的令牌可能被覆盖
+func (c *Cache) Put(server, username string, entry Entry) error {
+	entries, err := c.load()
+	if err != nil {
+		return err
+	}
+	for k, e := range entries {
+		if !e.Valid() {
+			delete(entries, k)
+		}
+	}
+	entries[key(server, username)] = entry
+	return c.save(entries)
+}
+
+// Delete 删除令牌，与 Put 一样调用方需持有 Lock 返回的锁
+func (c *Cache) Delete(server, username string) error {
+	entries, err := c.load()
+	if err != nil {
+		return err
+	}
+	delete(entries, key(server, username))
+	return c.save(entries)
+}
+
+// Lock 获取缓存的排他文件锁，返回释放锁的函数。锁不可重入，Put 和 Delete 不会自行加锁
+func (c *Cache) Lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0700); err != nil {
+		return nil, fmt.Errorf("创建令牌缓存目录失败: %w", err)
+	}
+	f, err := os.OpenFile(c.Path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("打开令牌缓存锁失败: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("锁定令牌缓存失败: %w", err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+func (c *Cache) load() (map[string]Entry, error) {
+	entries := make(map[string]Entry)
+
+	data, err := os.ReadFile(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取令牌缓存失败: %w", err)
+	}
+	// 缓存损坏时当作空缓存处理，下次写入会覆盖
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return make(map[string]Entry), nil
+	}
+	return entries, nil
+}
+
+func (c *Cache) save(entries map[string]Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(c.Path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("创建令牌缓存目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".tokens-*")
+	if err != nil {
+		return fmt.Errorf("写入令牌缓存失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入令牌缓存失败: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入令牌缓存失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入令牌缓存失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.Path); err != nil {
+		return fmt.Errorf("写入令牌缓存失败: %w", err)
+	}
+	return nil
+}
+
+func key(server, username string) string {
+	return username + "@" + server
+}
@@ -0,0 +1,161 @@
+package tokencache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func validEntry(token string) Entry {
+	return Entry{Token: token, Expiry: time.Now().Add(time.Hour).Unix()}
+}
+
+func TestEntryValid(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		entry Entry
+		want  bool
+	}{
+		{"valid", Entry{Token: "t", Expiry: now.Add(time.Hour).Unix()}, true},
+		{"expired", Entry{Token: "t", Expiry: now.Add(-time.Minute).Unix()}, false},
+		{"within margin", Entry{Token: "t", Expiry: now.Add(expiryMargin / 2).Unix()}, false},
+		{"empty token", Entry{Expiry: now.Add(time.Hour).Unix()}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.Valid(); got != tt.want {
+			t.Errorf("%s: Valid() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCachePerAccount(t *testing.T) {
+	cache := New(filepath.Join(t.TempDir(), "nacos", "tokens.json"))
+	if _, ok, err := cache.Get("http://a:8848", "nacos"); err != nil || ok {
+		t.Fatalf("Get() on missing file = %v, %v, want no entry", ok, err)
+	}
+
+	accounts := []struct{ server, username, token string }{
+		{"http://a:8848", "nacos", "t1"},
+		{"http://a:8848", "dev", "t2"},
+		{"http://b:8848", "nacos", "t3"},
+	}
+	for _, a := range accounts {
+		if err := cache.Put(a.server, a.username, validEntry(a.token)); err != nil {
+			t.Fatalf("Put() error: %v", err)
+		}
+	}
+	for _, a := range accounts {
+		entry, ok, err := New(cache.Path).Get(a.server, a.username)
+		if err != nil || !ok || entry.Token != a.token {
+			t.Errorf("Get(%s, %s) = %v, %v, %v, want %s", a.server, a.username, entry, ok, err, a.token)
+		}
+	}
+
+	if err := cache.Delete("http://a:8848", "nacos"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if _, ok, _ := cache.Get("http://a:8848", "nacos"); ok {
+		t.Error("Get() after Delete() found the token")
+	}
+	if entry, ok, _ := cache.Get("http://b:8848", "nacos"); !ok || entry.Token != "t3" {
+		t.Errorf("Delete() removed the token of another server: %v, %v", entry, ok)
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	cache := New(filepath.Join(t.TempDir(), "tokens.json"))
+	expired := Entry{Token: "old", Expiry: time.Now().Add(-time.Hour).Unix()}
+	if err := cache.Put("http://a:8848", "old", expired); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if _, ok, _ := cache.Get("http://a:8848", "old"); ok {
+		t.Error("Get() returned an expired token")
+	}
+
+	// 保存其他令牌时清理过期的条目
+	if err := cache.Put("http://a:8848", "new", validEntry("t")); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	entries, err := cache.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := entries[key("http://a:8848", "old")]; ok || len(entries) != 1 {
+		t.Errorf("entries after Put() = %v, want only the new token", entries)
+	}
+}
+
+func TestCacheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := os.WriteFile(path, []byte("{corrupt"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cache := New(path)
+	// 损坏的缓存当作空缓存，写入时覆盖
+	if _, ok, err := cache.Get("http://a:8848", "nacos"); err != nil || ok {
+		t.Errorf("Get() on corrupt file = %v, %v, want no entry", ok, err)
+	}
+	if err := cache.Put("http://a:8848", "nacos", validEntry("t")); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+	if _, ok, _ := cache.Get("http://a:8848", "nacos"); !ok {
+		t.Error("Get() after overwriting corrupt file found no token")
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("cache file mode = %o, want 600", perm)
+	}
+}
+
+func TestCacheConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	const writers = 20
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			// 每个写入者使用独立的 Cache，与多个进程一样通过文件锁串行化
+			cache := New(path)
+			unlock, err := cache.Lock()
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer unlock()
+			if err := cache.Put("http://a:8848", fmt.Sprintf("user%d", i), validEntry(fmt.Sprintf("t%d", i))); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("concurrent Put() error: %v", err)
+	}
+
+	cache := New(path)
+	for i := 0; i < writers; i++ {
+		entry, ok, err := cache.Get("http://a:8848", fmt.Sprintf("user%d", i))
+		if err != nil || !ok || entry.Token != fmt.Sprintf("t%d", i) {
+			t.Errorf("Get(user%d) = %v, %v, %v, want t%d", i, entry, ok, err, i)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package validate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// 没有结束标签的空元素
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// 可以省略结束标签的元素
+var optionalEndElements = map[string]bool{
+	"html": true, "head": true, "body": true, "p": true, "li": true, "dt": true, "dd": true,
+	"option": true, "optgroup": true, "thead": true, "tbody": true, "tfoot": true, "tr": true,
+	"td": true, "th": true, "colgroup": true, "rt": true, "rp": true,
+}
+
+// 内容为纯文本的元素，其中的 < 不是标签
+var rawTextElements = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true,
+}
+
+type openElement struct {
+	name   string
+	offset int
+}
+
+// 检查 html 的标签是否正确闭合。html 的语法很宽松，这里只检查注释和标签是否完整、
+// 除空元素和可省略结束标签的元素外每个元素都有匹配的结束标签
+func checkHTML(content []byte) error {
+	fail := func(offset int, format string, args ...interface{}) error {
+		line, column := position(content, offset)
+		return &SyntaxError{Type: "html", Line: line, Column: column, Msg: fmt.Sprintf(format, args...)}
+	}
+
+	var stack []openElement
+	lower := bytes.ToLower(content)
+	for i := 0; i < len(content); i++ {
+		if content[i] != '<' {
+			continue
+		}
+		rest := content[i:]
+		switch {
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			end := bytes.Index(rest[4:], []byte("-->"))
+			if end < 0 {
+				return fail(i, "注释没有结束")
+			}
+			i += 4 + end + 2
+		case bytes.HasPrefix(rest, []byte("<!")), bytes.HasPrefix(rest, []byte("<?")):
+			end := bytes.IndexByte(rest, '>')
+			if end < 0 {
+				return fail(i, "声明没有结束")
+			}
+			i += end
+		case bytes.HasPrefix(rest, []byte("</")):
+			end := bytes.IndexByte(rest, '>')
+			if end < 0 {
+				return fail(i, "结束标签没有结束")
+			}
+			name := strings.ToLower(strings.TrimSpace(string(rest[2:end])))
+			if name == "" || !isTagName(name) {
+				return fail(i, "无效的结束标签 %s", rest[:end+1])
+			}
+			// 弹出可以省略结束标签的元素，直到找到匹配的开始标签
+			j := len(stack) - 1
+			for j >= 0 && stack[j].name != name && optionalEndElements[stack[j].name] {
+				j--
+			}
+			if j < 0 || stack[j].name != name {
+				if j >= 0 {
+					line, column := position(content, stack[j].offset)
+					return fail(i, "结束标签 </%s> 与第%d行第%d列的 <%s> 不匹配", name, line, column, stack[j].name)
+				}
+				return fail(i, "多余的结束标签 </%s>", name)
+			}
+			stack = stack[:j]
+			i += end
+		default:
+			if i+1 >= len(content) || !isTagStart(content[i+1]) {
+				// 文本中的 <，html 允许这样写
+				continue
+			}
+			end, selfClosing, ok := tagEnd(content, i)
+			if !ok {
+				return fail(i, "开始标签没有结束")
+			}
+			nameEnd := i + 1
+			for nameEnd < end && isTagNameByte(content[nameEnd]) {
+				nameEnd++
+			}
+			name := strings.ToLower(string(content[i+1 : nameEnd]))
+			i = end
+			if voidElements[name] || selfClosing {
+				continue
+			}
+			if rawTextElements[name] {
+				closing := []byte("</" + name)
+				k := bytes.Index(lower[i+1:], closing)
+				if k < 0 {
+					return fail(end, "<%s> 没有结束标签", name)
+				}
+				closeStart := i + 1 + k
+				gt := bytes.IndexByte(content[closeStart:], '>')
+				if gt < 0 {
+					return fail(closeStart, "结束标签没有结束")
+				}
+				i = closeStart + gt
+				continue
+			}
+			stack = append(stack, openElement{name: name, offset: nameEnd - len(name) - 1})
+		}
+	}
+
+	for j := len(stack) - 1; j >= 0; j-- {
+		if !optionalEndElements[stack[j].name] {
+			return fail(stack[j].offset, "<%s> 没有结束标签", stack[j].name)
+		}
+	}
+	return nil
+}
+
+// 开始标签的结束位置（> 的下标），跳过引号中的属性值
+func tagEnd(content []byte, start int) (int, bool, bool) {
+	var quote byte
+	for i := start + 1; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i, content[i-1] == '/', true
+		case c == '<':
+			return 0, false, false
+		}
+	}
+	return 0, false, false
+}
+
+func isTagStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isTagNameByte(c byte) bool {
+	return isTagStart(c) || c >= '0' && c <= '9' || c == '-' || c == ':' || c == '_'
+}
+
+func isTagName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if !isTagNameByte(name[i]) {
+			return false
+		}
+	}
+	return isTagStart(name[0])
+}
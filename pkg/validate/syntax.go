@@ -0,0 +1,221 @@
+// Package validate 按配置类型检查配置内容的语法
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// SyntaxError 配置内容的语法错误，Line 和 Column 从1开始，为0表示未知
+type SyntaxError struct {
+	Type   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s 语法错误 (第%d行第%d列): %s", e.Type, e.Line, e.Column, e.Msg)
+	case e.Line > 0:
+		return fmt.Sprintf("%s 语法错误 (第%d行): %s", e.Type, e.Line, e.Msg)
+	default:
+		return fmt.Sprintf("%s 语法错误: %s", e.Type, e.Msg)
+	}
+}
+
+// Supported 是否支持检查该类型的语法
+func Supported(configType string) bool {
+	switch strings.ToLower(configType) {
+	case "yaml", "json", "xml", "properties", "toml", "html":
+		return true
+	}
+	return false
+}
+
+// Syntax 按配置类型检查内容的语法，不支持的类型（如 text）不做检查
+func Syntax(configType string, content []byte) error {
+	switch strings.ToLower(configType) {
+	case "yaml":
+		return checkYAML(content)
+	case "json":
+		return checkJSON(content)
+	case "xml":
+		return checkXML(content)
+	case "properties":
+		return checkProperties(content)
+	case "toml":
+		return checkTOML(content)
+	case "html":
+		return checkHTML(content)
+	}
+	return nil
+}
+
+var yamlLinePattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+func checkYAML(content []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			msg := err.Error()
+			if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+				line, _ := strconv.Atoi(m[1])
+				return &SyntaxError{Type: "yaml", Line: line, Msg: m[2]}
+			}
+			return &SyntaxError{Type: "yaml", Msg: strings.TrimPrefix(msg, "yaml: ")}
+		}
+	}
+}
+
+func checkJSON(content []byte) error {
+	var v interface{}
+	err := json.Unmarshal(content, &v)
+	if err == nil {
+		return nil
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset 是出错时已读取的字节数，出错的字符在其前一个字节
+		offset := int(syntaxErr.Offset) - 1
+		if offset < 0 {
+			offset = 0
+		}
+		line, column := position(content, offset)
+		return &SyntaxError{Type: "json", Line: line, Column: column, Msg: syntaxErr.Error()}
+	}
+	return &SyntaxError{Type: "json", Msg: err.Error()}
+}
+
+func checkXML(content []byte) error {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	decoder.Strict = true
+	for {
+		_, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			line, column := decoder.InputPos()
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return &SyntaxError{Type: "xml", Line: syntaxErr.Line, Column: column, Msg: syntaxErr.Msg}
+			}
+			return &SyntaxError{Type: "xml", Line: line, Column: column, Msg: err.Error()}
+		}
+	}
+}
+
+func checkTOML(content []byte) error {
+	var v map[string]interface{}
+	err := toml.Unmarshal(content, &v)
+	if err == nil {
+		return nil
+	}
+	var decodeErr *toml.DecodeError
+	if errors.As(err, &decodeErr) {
+		line, column := decodeErr.Position()
+		return &SyntaxError{Type: "toml", Line: line, Column: column, Msg: decodeErr.Error()}
+	}
+	return &SyntaxError{Type: "toml", Msg: err.Error()}
+}
+
+// Java 的 properties 格式非常宽松，唯一的语法错误是不完整的 \uXXXX 转义。
+// 与 Java 一样跳过 # 和 ! 开头的注释行，并把以反斜杠续行的多个物理行合并为一个逻辑行后再检查
+func checkProperties(content []byte) error {
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		text := strings.TrimLeft(lines[i], " \t\f")
+		if text == "" || text[0] == '#' || text[0] == '!' {
+			continue
+		}
+
+		// 逻辑行由各物理行去掉开头空白和续行反斜杠后拼接而成，segments 记录每段在物理行中的位置
+		var logical strings.Builder
+		var segments []propertySegment
+		for {
+			segments = append(segments, propertySegment{
+				start: logical.Len(), line: i, offset: len(lines[i]) - len(text),
+			})
+			if !continues(text) {
+				logical.WriteString(text)
+				break
+			}
+			logical.WriteString(text[:len(text)-1])
+			if i+1 == len(lines) {
+				break
+			}
+			i++
+			text = strings.TrimLeft(lines[i], " \t\f")
+		}
+
+		line := logical.String()
+		for j := 0; j < len(line); j++ {
+			if line[j] != '\\' {
+				continue
+			}
+			if j+1 < len(line) && line[j+1] == 'u' && (j+6 > len(line) || !isHex(line[j+2:j+6])) {
+				k := len(segments) - 1
+				for segments[k].start > j {
+					k--
+				}
+				physical := lines[segments[k].line]
+				column := utf8.RuneCountInString(physical[:segments[k].offset+j-segments[k].start]) + 1
+				return &SyntaxError{Type: "properties", Line: segments[k].line + 1, Column: column, Msg: "不完整的 \\uXXXX 转义"}
+			}
+			j++
+		}
+	}
+	return nil
+}
+
+// 逻辑行中的一段，start 是在逻辑行中的起始下标，line 和 offset 是对应的物理行下标和行内字节偏移
+type propertySegment struct {
+	start, line, offset int
+}
+
+// 行尾是否为未转义的反斜杠
+func continues(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// 将从0开始的字节偏移转为从1开始的行号和列号，列号按字符计算
+func position(content []byte, offset int) (int, int) {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return line, column
+}
@@ -0,0 +1,111 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSyntaxValid(t *testing.T) {
+	tests := []struct {
+		name, configType, content string
+	}{
+		{"yaml", "yaml", "a: 1\nb:\n  - x\n"},
+		{"yaml multiple documents", "yaml", "a: 1\n---\nb: 2\n"},
+		{"json", "json", `{"a": [1, 2], "b": null}`},
+		{"xml", "xml", "<?xml version=\"1.0\"?>\n<a><b x=\"1\"/></a>\n"},
+		{"toml", "toml", "a = 1\n[b]\nc = \"x\"\n"},
+		{"properties", "properties", "a=1\nname=\\u8ba2\\u5355\n"},
+		{"properties comments", "properties", "# see C:\\users\\foo\n! path \\user\n  # \\u12\na=1\n"},
+		{"properties escaped backslash", "properties", "path=C:\\\\users\n"},
+		{"properties escape across continuation", "properties", "a=\\u8b\\\n  a2\n"},
+		{"properties continuation starting with #", "properties", "a=1,\\\n# 2\n"},
+		{"html void elements", "html", "<p>a<br>b<img src=\"x.png\"><input type=text></p>"},
+		{"html optional end tags", "html", "<html><body><p>a<p>b<ul><li>x<li>y</ul><table><tr><td>1<td>2</table></body>"},
+		{"html raw text", "html", "<script>if (a < b && c > d) { s = '</div>' }</script><style>a>b{}</style>"},
+		{"html uppercase raw text end", "html", "<SCRIPT>x</script >"},
+		{"html stray <", "html", "<p>a < b and 1<2</p>"},
+		{"html quoted >", "html", "<a title=\"a>b\" href='c>d'>x</a>"},
+		{"html self closing and comments", "html", "<!DOCTYPE html><!-- <div> --><x-y/><svg><path d=\"M0\"/></svg>"},
+		{"unsupported type", "text", "anything {"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Syntax(tt.configType, []byte(tt.content)); err != nil {
+				t.Errorf("Syntax(%q) error: %v", tt.content, err)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name, configType, content string
+		line, column              int
+		msg                       string
+	}{
+		{"yaml", "yaml", "a: 1\nb: [\n", 2, 0, "did not find expected node content"},
+		{"yaml second document", "yaml", "a: 1\n---\nb: :\n", 3, 0, "mapping values are not allowed"},
+		{"json", "json", "{\n  \"a\": 1,\n  \"b\" 2\n}", 3, 7, "invalid character '2' after object key"},
+		{"json unexpected end", "json", `{"a": 1`, 1, 7, "unexpected end of JSON input"},
+		{"json column counts characters", "json", `{"名称": x}`, 1, 8, "invalid character 'x'"},
+		{"xml", "xml", "<a>\n  <b></a>\n", 2, 10, "element <b> closed by </a>"},
+		{"toml", "toml", "a = 1\nb = \n", 2, 5, "incomplete number"},
+		{"properties", "properties", "a=1\nb=x\\u12\n", 2, 4, "不完整的 \\uXXXX 转义"},
+		{"properties column counts characters", "properties", "名称=\\uzzzz\n", 1, 4, "不完整的 \\uXXXX 转义"},
+		{"properties continuation", "properties", "a=1,\\\n    \\u12\n", 2, 5, "不完整的 \\uXXXX 转义"},
+		{"properties crlf", "properties", "a=1\r\n  b=\\u\r\n", 2, 5, "不完整的 \\uXXXX 转义"},
+		{"html mismatched end tag", "html", "<div>\n  <span></div>", 2, 9, "结束标签 </div> 与第2行第3列的 <span> 不匹配"},
+		{"html unclosed element", "html", "<p><div>x</p>", 1, 10, "结束标签 </p> 与第1行第4列的 <div> 不匹配"},
+		{"html missing end tag", "html", "<script>a < b</script>\n<div>", 2, 1, "<div> 没有结束标签"},
+		{"html unclosed raw text", "html", "<textarea>x", 1, 10, "<textarea> 没有结束标签"},
+		{"html extra end tag", "html", "</div>", 1, 1, "多余的结束标签 </div>"},
+		{"html unclosed start tag", "html", "<p>\n<div", 2, 1, "开始标签没有结束"},
+		{"html unclosed comment", "html", "<p></p><!-- x", 1, 8, "注释没有结束"},
+		{"html invalid end tag", "html", "<p></1p>", 1, 4, "无效的结束标签 </1p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Syntax(tt.configType, []byte(tt.content))
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Syntax(%q) error = %v, want SyntaxError", tt.content, err)
+			}
+			if syntaxErr.Type != tt.configType || syntaxErr.Line != tt.line || syntaxErr.Column != tt.column || !strings.Contains(syntaxErr.Msg, tt.msg) {
+				t.Errorf("Syntax(%q) error = %s %d:%d %q, want %s %d:%d %q", tt.content,
+					syntaxErr.Type, syntaxErr.Line, syntaxErr.Column, syntaxErr.Msg, tt.configType, tt.line, tt.column, tt.msg)
+			}
+		})
+	}
+}
+
+func TestSyntaxErrorString(t *testing.T) {
+	tests := []struct {
+		err  *SyntaxError
+		want string
+	}{
+		{&SyntaxError{Type: "json", Line: 2, Column: 3, Msg: "x"}, "json 语法错误 (第2行第3列): x"},
+		{&SyntaxError{Type: "yaml", Line: 2, Msg: "x"}, "yaml 语法错误 (第2行): x"},
+		{&SyntaxError{Type: "toml", Msg: "x"}, "toml 语法错误: x"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for _, configType := range []string{"yaml", "JSON", "xml", "properties", "toml", "html"} {
+		if !Supported(configType) {
+			t.Errorf("Supported(%q) = false, want true", configType)
+		}
+	}
+	for _, configType := range []string{"text", "", "ini"} {
+		if Supported(configType) {
+			t.Errorf("Supported(%q) = true, want false", configType)
+		}
+	}
+}